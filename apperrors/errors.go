@@ -0,0 +1,89 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Code classifies an error for API clients. It is exposed as extensions.code.
+type Code string
+
+const (
+	NotFound      Code = "NOT_FOUND"
+	AlreadyExists Code = "ALREADY_EXISTS"
+	Validation    Code = "VALIDATION"
+	Conflict      Code = "CONFLICT"
+	Internal      Code = "INTERNAL"
+)
+
+// Error is an error that can be safely shown to API clients.
+// Field names the argument or field that caused it, Err keeps the underlying cause for logs only.
+type Error struct {
+	Code       Code
+	Message    string
+	Field      string
+	Extensions map[string]interface{}
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithExtension attaches additional data returned to the client next to the code
+func (e *Error) WithExtension(key string, value interface{}) *Error {
+	if e.Extensions == nil {
+		e.Extensions = map[string]interface{}{}
+	}
+	e.Extensions[key] = value
+	return e
+}
+
+// NewNotFound reports that the entity identified by field does not exist
+func NewNotFound(field string, format string, args ...interface{}) *Error {
+	return &Error{Code: NotFound, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewAlreadyExists reports a uniqueness conflict on field
+func NewAlreadyExists(field string, format string, args ...interface{}) *Error {
+	return &Error{Code: AlreadyExists, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewValidation reports an invalid value of field
+func NewValidation(field string, format string, args ...interface{}) *Error {
+	return &Error{Code: Validation, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewConflict reports that the entity changed since the client read it, e.g. a stale expected version
+func NewConflict(field string, format string, args ...interface{}) *Error {
+	return &Error{Code: Conflict, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewInternal hides err behind a generic message. The cause is only logged.
+func NewInternal(err error) *Error {
+	return &Error{Code: Internal, Message: "internal server error", Err: err}
+}
+
+// FromDB converts a database error. Unique violations become ALREADY_EXISTS on field,
+// anything else is reported as INTERNAL. It requires gorm.Config.TranslateError.
+func FromDB(err error, field string, format string, args ...interface{}) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		e := NewAlreadyExists(field, format, args...)
+		e.Err = err
+		return e
+	}
+	return NewInternal(err)
+}
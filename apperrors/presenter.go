@@ -0,0 +1,68 @@
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Presenter is a gqlgen ErrorPresenter that exposes the error code and the offending field in extensions.
+// Errors which are not *Error are logged and replaced by a generic INTERNAL error, so database
+// details never reach clients. Errors produced by gqlgen itself keep the code gqlgen gave them (parse and
+// validation failures), arguments it cannot unmarshal are marked as VALIDATION and anything else is INTERNAL.
+func Presenter(ctx context.Context, err error) *gqlerror.Error {
+	var appErr *Error
+	var gqlErr *gqlerror.Error
+
+	switch {
+	case errors.As(err, &appErr):
+	case errors.As(err, &gqlErr):
+		presented := graphql.DefaultErrorPresenter(ctx, err)
+		if _, ok := presented.Extensions["code"]; ok {
+			return presented
+		}
+		if argumentError(ctx, gqlErr) {
+			if presented.Extensions == nil {
+				presented.Extensions = map[string]interface{}{}
+			}
+			presented.Extensions["code"] = Validation
+			return presented
+		}
+		// E.g. a null resolved for a non-null field, which is a fault of the server
+		appErr = NewInternal(err)
+	default:
+		appErr = NewInternal(err)
+	}
+
+	if appErr.Code == Internal {
+		log.Printf("internal error at %v: %v", graphql.GetPath(ctx), appErr)
+	}
+
+	presented := graphql.DefaultErrorPresenter(ctx, errors.New(appErr.Message))
+	presented.Extensions = map[string]interface{}{"code": appErr.Code}
+	for key, value := range appErr.Extensions {
+		presented.Extensions[key] = value
+	}
+	if appErr.Field != "" {
+		presented.Extensions["field"] = appErr.Field
+	}
+
+	return presented
+}
+
+// argumentError tells whether gqlgen failed to unmarshal an argument of the field being resolved.
+// Such errors point at the argument, below the path of the field.
+func argumentError(ctx context.Context, gqlErr *gqlerror.Error) bool {
+	return graphql.GetFieldContext(ctx) != nil && len(gqlErr.Path) > len(graphql.GetPath(ctx))
+}
+
+// Recover is a gqlgen RecoverFunc which turns a panic of a resolver into an INTERNAL error.
+// Presenter logs it with the stack of the panic, clients only get the generic message.
+func Recover(ctx context.Context, err interface{}) error {
+	return NewInternal(fmt.Errorf("panic: %v\n%s", err, debug.Stack()))
+}
@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"translatorapi/models"
+	"unicode/utf8"
+)
+
+const (
+	// authorHeader names the user a request acts for. Words, translations and examples it adds are created by that user.
+	// It is set by the authenticating proxy in front of the server, see AuthorMiddleware
+	authorHeader = "X-User"
+	// maxAuthorLength is the size of the created_by columns, in characters
+	maxAuthorLength = 255
+)
+
+// AuthorMiddleware attributes the rows created by each HTTP request to the user named in its X-User header.
+// Requests without the header stay anonymous, so createdBy is null.
+//
+// The header is not authenticated: any client can claim any name in it. It may only be trusted when the server
+// runs behind a proxy which authenticates users and sets X-User itself, replacing whatever the client sent.
+// An authentication middleware in front of this one can instead store the author with models.WithAuthor;
+// the header is then ignored.
+func AuthorMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if models.Author(r.Context()) != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		author := strings.TrimSpace(r.Header.Get(authorHeader))
+		if utf8.RuneCountInString(author) > maxAuthorLength {
+			http.Error(w, fmt.Sprintf("%s must be at most %d characters", authorHeader, maxAuthorLength), http.StatusBadRequest)
+			return
+		}
+		if author != "" {
+			r = r.WithContext(models.WithAuthor(r.Context(), author))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
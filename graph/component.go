@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/lemmatizer"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+)
+
+// setKind applies the entry kind to the word, a nil kind leaves it unchanged.
+// Only multi-word entries have components, so a stored word keeping components cannot become a WORD.
+func setKind(tx *gorm.DB, word *models.Word, kind *model.EntryKind) error {
+	if kind == nil {
+		return nil
+	}
+
+	if *kind == model.EntryKindWord && word.ID != 0 {
+		var count int64
+		if err := tx.Model(&models.WordComponent{}).Where("word_id = ?", word.ID).Count(&count).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+		if count > 0 {
+			return apperrors.NewValidation("kind", "a WORD entry cannot have components, remove them first")
+		}
+	}
+
+	word.Kind = kind.String()
+	return nil
+}
+
+// setComponents replaces the components of the entry with the words spelled as terms, in the entry's language.
+// Components are stored headwords, so a missing one fails with NOT_FOUND and suggestions.
+func setComponents(tx *gorm.DB, entry models.Word, terms []string) ([]models.Word, error) {
+	if entry.Kind == model.EntryKindWord.String() && len(terms) > 0 {
+		return nil, apperrors.NewValidation("components", "a WORD entry cannot have components, set its kind first")
+	}
+
+	if err := tx.Where("word_id = ?", entry.ID).Delete(&models.WordComponent{}).Error; err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
+	components := make([]models.Word, 0, len(terms))
+	for i, term := range terms {
+		component, err := findTerm(tx, "components", "component", term, entry.LanguageCode)
+		if err != nil {
+			return nil, err
+		}
+		if component.ID == entry.ID {
+			return nil, apperrors.NewValidation("components", "an entry cannot be its own component")
+		}
+
+		link := models.WordComponent{WordID: entry.ID, ComponentWordID: component.ID, Position: i + 1}
+		if err := tx.Create(&link).Error; err != nil {
+			return nil, apperrors.NewInternal(err)
+		}
+		components = append(components, component)
+	}
+	return components, nil
+}
+
+// findEntriesContaining finds the entries of the kind having the word spelled term among their components.
+// The term may be an inflected form, resolved to its words by findLemmas.
+func findEntriesContaining(db *gorm.DB, lem lemmatizer.Lemmatizer, term string, languageCode string, kind model.EntryKind) ([]models.Word, error) {
+	matches, err := findLemmas(db, lem, term, languageCode)
+	if err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+	if len(matches) == 0 {
+		return nil, notFoundError(db, "term", "word", term, languageCode)
+	}
+
+	wordIDs := make([]uint, 0, len(matches))
+	for _, match := range matches {
+		wordIDs = append(wordIDs, match.Word.ID)
+	}
+
+	entryIDs := db.Model(&models.WordComponent{}).Select("word_id").Where("component_word_id IN ?", wordIDs)
+
+	var entries []models.Word
+	if err := db.Where("kind = ? AND id IN (?)", kind.String(), entryIDs).Order("term, id").Find(&entries).Error; err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+	return entries, nil
+}
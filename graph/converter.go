@@ -1,56 +1,139 @@
 package graph
 
 import (
-	"strconv"
 	"translatorapi/graph/model"
 	"translatorapi/models"
 )
 
 // Funkcja konwertująca Word na GraphQL Word
 func ToGraphQLWord(word *models.Word) *model.Word {
-	// Konwersja int na string, jeśli pole Word.ID jest int
+	// ID jest kodowane jako globalne ID (typ i klucz w base64)
 	return &model.Word{
-		ID:         strconv.Itoa(int(word.ID)), // int na string
-		PolishWord: word.PolishWord,
-		Translations: func() []*model.Translation {
-			// Tworzenie pustej tablicy Translation
-			translations := make([]*model.Translation, 0)
-			// Iteracja po wszystkich Translation
-			for _, t := range word.Translations {
-				// Konwersja Translation na GraphQL Translation
-				translations = append(translations, ToGraphQLTranslation(&t))
-			}
-			return translations
-		}(),
+		ID:           toGlobalID(wordType, word.ID), // globalne ID typu Word
+		Term:         word.Term,
+		LanguageCode: word.LanguageCode,
+		PolishWord:   word.Term, // przestarzałe pole, zgodne wstecz
+		Kind:         model.EntryKind(word.Kind),
+		// Metadane gramatyczne są zapisane jako nazwy enumów GraphQL
+		PartOfSpeech: enumValue[model.PartOfSpeech](word.PartOfSpeech),
+		Gender:       enumValue[model.Gender](word.Gender),
+		Aspect:       enumValue[model.Aspect](word.Aspect),
+		Note:         word.Note,
+		// Znaczniki czasu i autor (nil, gdy nieznany)
+		CreatedAt: word.CreatedAt,
+		UpdatedAt: word.UpdatedAt,
+		CreatedBy: word.CreatedBy,
+		Version:   int32(word.Version), // licznik zmian, sprawdzany przez expectedVersion
+		// Tłumaczenia są ładowane przez resolver pola translations (DataLoader)
 	}
 }
 
 // Funkcja konwertująca Translation na GraphQL Translation
 func ToGraphQLTranslation(t *models.Translation) *model.Translation {
-	// Konwersja int na string, jeśli pole Translation.ID jest int
+	// ID jest kodowane jako globalne ID (typ i klucz w base64)
+	// Oba słowa (źródłowe i docelowe) muszą być załadowane, np. przez withTerms
 	return &model.Translation{
-		ID:          strconv.Itoa(int(t.ID)),     // int na string
-		WordID:      strconv.Itoa(int(t.WordID)), // Konwersja int na string
-		EnglishWord: t.EnglishWord,
-		Examples: func() []*model.Example {
-			// Tworzenie pustej tablicy Example
-			examples := make([]*model.Example, 0)
-			// Iteracja po wszystkich Example
-			for _, e := range t.Examples {
-				// Konwersja Example na GraphQL Example
-				examples = append(examples, ToGraphQLExample(&e))
-			}
-			return examples
-		}(),
+		ID:             toGlobalID(translationType, t.ID),    // globalne ID typu Translation
+		WordID:         toGlobalID(wordType, t.WordID),       // globalne ID słowa źródłowego
+		TargetWordID:   toGlobalID(wordType, t.TargetWordID), // globalne ID słowa docelowego
+		SourceTerm:     t.Word.Term,
+		SourceLanguage: t.Word.LanguageCode,
+		TargetTerm:     t.TargetWord.Term,
+		TargetLanguage: t.TargetWord.LanguageCode,
+		EnglishWord:    t.TargetWord.Term, // przestarzałe pole, zgodne wstecz
+		Rank:           int32(t.Rank),     // pozycja wśród tłumaczeń słowa, 1 to znaczenie główne
+		// Etykiety użycia są zapisane jako nazwy enumów GraphQL (dziedzina jako tekst)
+		Register: enumValue[model.Register](t.Register),
+		Domain:   t.Domain,
+		Region:   enumValue[model.Region](t.Region),
+		// Znaczniki czasu i autor (nil, gdy nieznany)
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+		CreatedBy: t.CreatedBy,
+		Version:   int32(t.Version), // licznik zmian, sprawdzany przez expectedVersion
+		// Przykłady są ładowane przez resolver pola examples (DataLoader)
+	}
+}
+
+// Funkcja konwertująca Translation na GraphQL PolishTranslation (kierunek angielski → polski)
+func ToGraphQLPolishTranslation(t *models.Translation) *model.PolishTranslation {
+	return &model.PolishTranslation{
+		ID:          toGlobalID(translationType, t.ID), // globalne ID typu Translation
+		WordID:      toGlobalID(wordType, t.WordID),    // globalne ID słowa
+		PolishWord:  t.Word.Term,
+		EnglishWord: t.TargetWord.Term,
 	}
 }
 
 // Funkcja konwertująca Example na GraphQL Example
 func ToGraphQLExample(e *models.Example) *model.Example {
-	// Konwersja int na string, jeśli pole Example.ID jest int
-	return &model.Example{
-		ID:            strconv.Itoa(int(e.ID)),            // int na string
-		TranslationID: strconv.Itoa(int(e.TranslationID)), // int na string
-		Sentence:      e.Sentence,
+	// ID jest kodowane jako globalne ID (typ i klucz w base64)
+	example := &model.Example{
+		ID:            toGlobalID(exampleType, e.ID),                // globalne ID typu Example
+		TranslationID: toGlobalID(translationType, e.TranslationID), // globalne ID tłumaczenia
+		SentenceID:    toGlobalID(sentenceType, e.SentenceID),       // globalne ID zdania
+		Sentence:      e.Sentence.Text,
+		LanguageCode:  e.LanguageCode,
+		Flagged:       e.Flagged, // zdanie nie zawiera słowa tłumaczenia (tryb FLAG)
+		// Znaczniki czasu i autor (nil, gdy nieznany)
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+		CreatedBy: e.CreatedBy,
+		Version:   int32(e.Version), // licznik zmian, sprawdzany przez expectedVersion
+	}
+	// Zdanie równoległe (tłumaczenie zdania) jest opcjonalne
+	if e.ParallelSentence != nil {
+		parallelSentenceID := toGlobalID(sentenceType, e.ParallelSentence.ID)
+		example.ParallelSentenceID = &parallelSentenceID
+		example.ParallelSentence = &e.ParallelSentence.Text
+		example.ParallelLanguageCode = e.ParallelLanguageCode
+	}
+	return example
+}
+
+// Funkcja konwertująca Inflection na GraphQL Inflection
+func ToGraphQLInflection(i *models.Inflection) *model.Inflection {
+	return &model.Inflection{
+		ID:     toGlobalID(inflectionType, i.ID), // globalne ID typu Inflection
+		WordID: toGlobalID(wordType, i.WordID),   // globalne ID słowa
+		Form:   i.Form,
+		// Kategorie gramatyczne są zapisane jako nazwy enumów GraphQL
+		GrammaticalCase: enumValue[model.GrammaticalCase](i.GrammaticalCase),
+		Number:          enumValue[model.GrammaticalNumber](i.Number),
+		Person:          enumValue[model.GrammaticalPerson](i.Person),
+		Version:         int32(i.Version), // licznik zmian, sprawdzany przez expectedVersion
+	}
+}
+
+// Funkcja konwertująca Revision na GraphQL Revision
+func ToGraphQLRevision(r *models.Revision) *model.Revision {
+	entityType := model.RevisionEntity(r.EntityType)
+	return &model.Revision{
+		ID:         toGlobalID(revisionType, r.ID),                          // globalne ID typu Revision
+		WordID:     toGlobalID(wordType, r.WordID),                          // globalne ID słowa, do którego historii należy zmiana
+		EntityID:   toGlobalID(revisionEntityTypes[entityType], r.EntityID), // globalne ID zmienionej encji
+		EntityType: entityType,
+		Action:     model.RevisionAction(r.Action),
+		Mutation:   r.Mutation,
+		// Migawki JSON przed i po zmianie (nil, gdy encja nie istniała)
+		Before:    r.Before,
+		After:     r.After,
+		CreatedAt: r.CreatedAt,
+		CreatedBy: r.CreatedBy,
+	}
+}
+
+// Funkcja konwertująca WordRelation na GraphQL WordRelation, widzianą od strony słowa wordID
+func ToGraphQLWordRelation(r *models.WordRelation, wordID uint) *model.WordRelation {
+	relationType := model.RelationType(r.Type)
+	// Relacja zapisana od słowa wordID prowadzi do słowa powiązanego
+	if r.WordID == wordID {
+		return &model.WordRelation{Type: relationType, Word: ToGraphQLWord(&r.RelatedWord)}
+	}
+	// Relacja zapisana w przeciwną stronę; relacje symetryczne nie mają kierunku
+	return &model.WordRelation{
+		Type:    relationType,
+		Word:    ToGraphQLWord(&r.Word),
+		Inverse: !isSymmetric(relationType),
 	}
 }
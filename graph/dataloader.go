@@ -0,0 +1,365 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/models"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"gorm.io/gorm"
+)
+
+const (
+	// loaderWait is how long a loader collects keys before fetching them in one query
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch caps the number of keys sent in a single IN (...) query
+	loaderMaxBatch = 500
+)
+
+// batchFunc fetches the values of many keys at once. Keys missing from the result get the zero value.
+type batchFunc[K comparable, V any] func(keys []K) (map[K]V, error)
+
+// loaderBatch is a group of keys waiting to be fetched together
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	done    chan struct{}
+	results map[K]V
+	err     error
+}
+
+// loader batches and caches lookups made while resolving one request.
+// Keys requested within loaderWait of each other are fetched with a single call to fetch.
+type loader[K comparable, V any] struct {
+	fetch batchFunc[K, V]
+
+	mu    sync.Mutex
+	cache map[K]V
+	batch *loaderBatch[K, V]
+}
+
+func newLoader[K comparable, V any](fetch batchFunc[K, V]) *loader[K, V] {
+	return &loader[K, V]{fetch: fetch, cache: make(map[K]V)}
+}
+
+// Load returns the value of the key, waiting for the batch it was added to
+func (l *loader[K, V]) Load(key K) (V, error) {
+	l.mu.Lock()
+	if value, ok := l.cache[key]; ok {
+		l.mu.Unlock()
+		return value, nil
+	}
+
+	batch := l.batch
+	if batch == nil {
+		batch = &loaderBatch[K, V]{done: make(chan struct{})}
+		l.batch = batch
+		go func() {
+			time.Sleep(loaderWait)
+			l.dispatch(batch)
+		}()
+	}
+	batch.keys = append(batch.keys, key)
+	if len(batch.keys) >= loaderMaxBatch {
+		go l.dispatch(batch)
+	}
+	l.mu.Unlock()
+
+	<-batch.done
+	return batch.results[key], batch.err
+}
+
+// clear forgets the cached values, batches already waiting still get their results
+func (l *loader[K, V]) clear() {
+	l.mu.Lock()
+	l.cache = make(map[K]V)
+	l.mu.Unlock()
+}
+
+// dispatch fetches a batch once, whichever of the timer or the size limit comes first
+func (l *loader[K, V]) dispatch(batch *loaderBatch[K, V]) {
+	l.mu.Lock()
+	if l.batch != batch {
+		// Already dispatched
+		l.mu.Unlock()
+		return
+	}
+	l.batch = nil
+	l.mu.Unlock()
+
+	batch.results, batch.err = l.fetch(uniqueKeys(batch.keys))
+
+	if batch.err == nil {
+		l.mu.Lock()
+		for _, key := range batch.keys {
+			l.cache[key] = batch.results[key]
+		}
+		l.mu.Unlock()
+	}
+	close(batch.done)
+}
+
+// uniqueKeys drops repeated keys, keeping the order of the first occurrence
+func uniqueKeys[K comparable](keys []K) []K {
+	seen := make(map[K]struct{}, len(keys))
+	unique := make([]K, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			unique = append(unique, key)
+		}
+	}
+	return unique
+}
+
+// Loaders holds the per-request loaders of nested fields
+type Loaders struct {
+	TranslationByID       *loader[uint, models.Translation]
+	TranslationsByWord    *loader[uint, []models.Translation]
+	ExamplesByTranslation *loader[uint, []models.Example]
+	InflectionsByWord     *loader[uint, []models.Inflection]
+	RelationsByWord       *loader[uint, []models.WordRelation]
+	ComponentsByWord      *loader[uint, []models.Word]
+}
+
+// NewLoaders creates empty loaders. They cache results, so they must not outlive a single request.
+func NewLoaders(db *gorm.DB) *Loaders {
+	return &Loaders{
+		TranslationByID: newLoader(func(ids []uint) (map[uint]models.Translation, error) {
+			var translations []models.Translation
+			if err := withTerms(db).Where("translations.id IN ?", ids).Find(&translations).Error; err != nil {
+				return nil, err
+			}
+
+			byID := make(map[uint]models.Translation, len(translations))
+			for _, t := range translations {
+				byID[t.ID] = t
+			}
+			return byID, nil
+		}),
+		TranslationsByWord: newLoader(func(wordIDs []uint) (map[uint][]models.Translation, error) {
+			var translations []models.Translation
+			if err := withTerms(db).
+				Where("translations.word_id IN ?", wordIDs).
+				Order(rankOrder).
+				Find(&translations).Error; err != nil {
+				return nil, err
+			}
+
+			byWord := make(map[uint][]models.Translation, len(wordIDs))
+			for _, t := range translations {
+				byWord[t.WordID] = append(byWord[t.WordID], t)
+			}
+			return byWord, nil
+		}),
+		ExamplesByTranslation: newLoader(func(translationIDs []uint) (map[uint][]models.Example, error) {
+			var examples []models.Example
+			if err := withSentences(db).
+				Where("examples.translation_id IN ?", translationIDs).
+				Order("examples.id").
+				Find(&examples).Error; err != nil {
+				return nil, err
+			}
+
+			byTranslation := make(map[uint][]models.Example, len(translationIDs))
+			for _, e := range examples {
+				byTranslation[e.TranslationID] = append(byTranslation[e.TranslationID], e)
+			}
+			return byTranslation, nil
+		}),
+		InflectionsByWord: newLoader(func(wordIDs []uint) (map[uint][]models.Inflection, error) {
+			var inflections []models.Inflection
+			if err := liveInflections(db).Where("word_id IN ?", wordIDs).Order("id").Find(&inflections).Error; err != nil {
+				return nil, err
+			}
+
+			byWord := make(map[uint][]models.Inflection, len(wordIDs))
+			for _, i := range inflections {
+				byWord[i.WordID] = append(byWord[i.WordID], i)
+			}
+			return byWord, nil
+		}),
+		ComponentsByWord: newLoader(func(wordIDs []uint) (map[uint][]models.Word, error) {
+			// Deleted components, and all components of a deleted entry, are skipped until they are restored
+			var components []models.WordComponent
+			if err := db.InnerJoins("ComponentWord").
+				Where("word_components.word_id IN ?", wordIDs).
+				Where("word_components.word_id IN (?)", db.Model(&models.Word{}).Select("id")).
+				Order("word_components.word_id, word_components.position").
+				Find(&components).Error; err != nil {
+				return nil, err
+			}
+
+			byWord := make(map[uint][]models.Word, len(wordIDs))
+			for _, c := range components {
+				byWord[c.WordID] = append(byWord[c.WordID], c.ComponentWord)
+			}
+			return byWord, nil
+		}),
+		// Relations are reached from both of their words
+		RelationsByWord: newLoader(func(wordIDs []uint) (map[uint][]models.WordRelation, error) {
+			var relations []models.WordRelation
+			if err := withRelatedWords(db).
+				Where("word_relations.word_id IN ? OR word_relations.related_word_id IN ?", wordIDs, wordIDs).
+				Order("word_relations.id").
+				Find(&relations).Error; err != nil {
+				return nil, err
+			}
+
+			byWord := make(map[uint][]models.WordRelation, len(wordIDs))
+			for _, r := range relations {
+				byWord[r.WordID] = append(byWord[r.WordID], r)
+				byWord[r.RelatedWordID] = append(byWord[r.RelatedWordID], r)
+			}
+			return byWord, nil
+		}),
+	}
+}
+
+// clear empties the caches of every loader
+func (l *Loaders) clear() {
+	l.TranslationByID.clear()
+	l.TranslationsByWord.clear()
+	l.ExamplesByTranslation.clear()
+	l.InflectionsByWord.clear()
+	l.RelationsByWord.clear()
+	l.ComponentsByWord.clear()
+}
+
+type loadersKey struct{}
+
+// LoaderMiddleware gives every HTTP request its own set of loaders
+func LoaderMiddleware(db *gorm.DB, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersKey{}, NewLoaders(db.WithContext(r.Context())))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ClearLoadersOnMutation is a gqlgen root field middleware emptying the request loaders before each mutation field.
+// Mutation fields run one after another, so a field never sees lists cached before an earlier field changed them.
+func ClearLoadersOnMutation(ctx context.Context, next graphql.RootResolver) graphql.Marshaler {
+	if graphql.GetOperationContext(ctx).Operation.Operation == ast.Mutation {
+		if loaders, ok := ctx.Value(loadersKey{}).(*Loaders); ok {
+			loaders.clear()
+		}
+	}
+	return next(ctx)
+}
+
+// loadersFor returns the loaders of the request.
+// Resolvers called without LoaderMiddleware (e.g. directly from tests) get fresh loaders, so they still work, only without batching.
+func loadersFor(ctx context.Context, db *gorm.DB) *Loaders {
+	if loaders, ok := ctx.Value(loadersKey{}).(*Loaders); ok {
+		return loaders
+	}
+	return NewLoaders(db.WithContext(ctx))
+}
+
+// loadTranslations resolves the translations of the word with the given global ID through the request loader.
+// A nil targetLanguage keeps the translations into every language.
+func loadTranslations(ctx context.Context, db *gorm.DB, wordGlobalID string, targetLanguage *string) ([]*model.Translation, error) {
+	wordID, err := fromGlobalID("id", wordGlobalID, wordType)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, err := loadersFor(ctx, db).TranslationsByWord.Load(wordID)
+	if err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
+	gqlTranslations := make([]*model.Translation, 0, len(translations))
+	for i := range translations {
+		// The loader is shared by fields asking for different languages, so it keeps them all
+		if targetLanguage != nil && translations[i].TargetWord.LanguageCode != *targetLanguage {
+			continue
+		}
+		gqlTranslations = append(gqlTranslations, ToGraphQLTranslation(&translations[i]))
+	}
+	return gqlTranslations, nil
+}
+
+// loadExamples resolves the examples of the translation with the given global ID through the request loader
+func loadExamples(ctx context.Context, db *gorm.DB, translationGlobalID string) ([]*model.Example, error) {
+	translationID, err := fromGlobalID("id", translationGlobalID, translationType)
+	if err != nil {
+		return nil, err
+	}
+
+	examples, err := loadersFor(ctx, db).ExamplesByTranslation.Load(translationID)
+	if err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
+	gqlExamples := make([]*model.Example, 0, len(examples))
+	for i := range examples {
+		gqlExamples = append(gqlExamples, ToGraphQLExample(&examples[i]))
+	}
+	return gqlExamples, nil
+}
+
+// loadInflections resolves the inflected forms of the word with the given global ID through the request loader
+func loadInflections(ctx context.Context, db *gorm.DB, wordGlobalID string) ([]*model.Inflection, error) {
+	wordID, err := fromGlobalID("id", wordGlobalID, wordType)
+	if err != nil {
+		return nil, err
+	}
+
+	inflections, err := loadersFor(ctx, db).InflectionsByWord.Load(wordID)
+	if err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
+	gqlInflections := make([]*model.Inflection, 0, len(inflections))
+	for i := range inflections {
+		gqlInflections = append(gqlInflections, ToGraphQLInflection(&inflections[i]))
+	}
+	return gqlInflections, nil
+}
+
+// loadRelated resolves the words related to the word with the given global ID through the request loader.
+// A nil relationType keeps the relations of every type.
+func loadRelated(ctx context.Context, db *gorm.DB, wordGlobalID string, relationType *model.RelationType) ([]*model.WordRelation, error) {
+	wordID, err := fromGlobalID("id", wordGlobalID, wordType)
+	if err != nil {
+		return nil, err
+	}
+
+	relations, err := loadersFor(ctx, db).RelationsByWord.Load(wordID)
+	if err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
+	gqlRelations := make([]*model.WordRelation, 0, len(relations))
+	for i := range relations {
+		if relationType != nil && relations[i].Type != relationType.String() {
+			continue
+		}
+		gqlRelations = append(gqlRelations, ToGraphQLWordRelation(&relations[i], wordID))
+	}
+	return gqlRelations, nil
+}
+
+// loadComponents resolves the components of the entry with the given global ID through the request loader
+func loadComponents(ctx context.Context, db *gorm.DB, wordGlobalID string) ([]*model.Word, error) {
+	wordID, err := fromGlobalID("id", wordGlobalID, wordType)
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := loadersFor(ctx, db).ComponentsByWord.Load(wordID)
+	if err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
+	gqlComponents := make([]*model.Word, 0, len(components))
+	for i := range components {
+		gqlComponents = append(gqlComponents, ToGraphQLWord(&components[i]))
+	}
+	return gqlComponents, nil
+}
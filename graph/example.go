@@ -0,0 +1,172 @@
+package graph
+
+import (
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/lemmatizer"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+)
+
+// exampleLanguages returns the language of an example sentence and of its parallel sentence.
+// The sentence is in one of the two languages of the translation, the source language unless languageCode says otherwise,
+// and the parallel sentence is in the other one. The translation needs both words loaded, e.g. with withTerms.
+func exampleLanguages(translation models.Translation, languageCode *string) (string, string, error) {
+	source, target := translation.Word.LanguageCode, translation.TargetWord.LanguageCode
+	if languageCode == nil || *languageCode == source {
+		return source, target, nil
+	}
+	if *languageCode == target {
+		return target, source, nil
+	}
+	return "", "", apperrors.NewValidation("languageCode", "example language must be %s or %s, got %s", source, target, *languageCode)
+}
+
+// otherLanguage returns the language of the translation which the example sentence is not in
+func otherLanguage(translation models.Translation, example models.Example) string {
+	if example.LanguageCode == translation.TargetWord.LanguageCode {
+		return translation.Word.LanguageCode
+	}
+	return translation.TargetWord.LanguageCode
+}
+
+// setParallelSentence gives the example its sentence in the other language, reusing a stored sentence.
+// An empty text removes the parallel sentence. The example is only changed in memory.
+func setParallelSentence(tx *gorm.DB, example *models.Example, text string, languageCode string) error {
+	if text == "" {
+		example.ParallelSentenceID = nil
+		example.ParallelSentence = nil
+		example.ParallelLanguageCode = nil
+		return nil
+	}
+
+	sentence, err := findOrCreateSentence(tx, text)
+	if err != nil {
+		return apperrors.NewInternal(err)
+	}
+	if sentence.ID == example.SentenceID {
+		return apperrors.NewValidation("parallelSentence", "parallel sentence must differ from the sentence")
+	}
+
+	example.ParallelSentenceID = &sentence.ID
+	example.ParallelSentence = &sentence
+	example.ParallelLanguageCode = &languageCode
+	return nil
+}
+
+// exampleTerm returns the word of the translation in the language of the example sentence
+func exampleTerm(translation models.Translation, example models.Example) models.Word {
+	if example.LanguageCode == translation.TargetWord.LanguageCode {
+		return translation.TargetWord
+	}
+	return translation.Word
+}
+
+// usesTerm tells whether the sentence contains the word, one of its stored inflected forms or a form recognized by the lemmatizer
+func usesTerm(sentence string, word models.Word, inflections []models.Inflection, lem lemmatizer.Lemmatizer) bool {
+	return len(findHighlights(sentence, []highlightTerm{newHighlightTerm(word, inflections, lem)})) > 0
+}
+
+// exampleValidation returns the validation mode of a call: the given one, else the server-wide one, else OFF
+func (r *Resolver) exampleValidation(validation *model.ExampleValidation) model.ExampleValidation {
+	if validation != nil {
+		return *validation
+	}
+	if r.ExampleValidation != "" {
+		return r.ExampleValidation
+	}
+	return model.ExampleValidationOff
+}
+
+// exampleValidator checks the sentence of a new or changed example against its translation.
+// It sets or clears example.Flagged, and fails with VALIDATION in REJECT mode.
+// The translation needs both words loaded and the example its sentence.
+type exampleValidator func(tx *gorm.DB, translation models.Translation, example *models.Example) error
+
+// exampleValidator returns the validator of the given mode, the server-wide one when nil
+func (r *Resolver) exampleValidator(validation *model.ExampleValidation) exampleValidator {
+	mode := r.exampleValidation(validation)
+
+	return func(tx *gorm.DB, translation models.Translation, example *models.Example) error {
+		example.Flagged = false
+		if mode == model.ExampleValidationOff {
+			return nil
+		}
+
+		word := exampleTerm(translation, *example)
+		var inflections []models.Inflection
+		if err := tx.Where("word_id = ?", word.ID).Find(&inflections).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+		if usesTerm(example.Sentence.Text, word, inflections, r.lemmatizerFor(word.LanguageCode)) {
+			return nil
+		}
+
+		if mode == model.ExampleValidationReject {
+			return apperrors.NewValidation("sentence", "sentence does not contain %q or any of its forms", word.Term)
+		}
+		example.Flagged = true
+		return nil
+	}
+}
+
+// lintBatchSize is the number of examples checked at once by lintExamples
+const lintBatchSize = 500
+
+// lintExamples checks every stored example, or those with sentences in languageCode, like the REJECT mode does.
+// Examples are read in batches, each with one query for its translations and one for the inflections of their words.
+func (r *Resolver) lintExamples(db *gorm.DB, languageCode *string) ([]*model.ExampleViolation, error) {
+	query := withSentences(db)
+	if languageCode != nil {
+		query = query.Where("examples.language_code = ?", *languageCode)
+	}
+
+	violations := []*model.ExampleViolation{}
+	var examples []models.Example
+	result := query.FindInBatches(&examples, lintBatchSize, func(tx *gorm.DB, batch int) error {
+		translationIDs := make([]uint, 0, len(examples))
+		for _, example := range examples {
+			translationIDs = append(translationIDs, example.TranslationID)
+		}
+
+		var translations []models.Translation
+		if err := withTerms(db).Where("translations.id IN ?", translationIDs).Find(&translations).Error; err != nil {
+			return err
+		}
+		translationsByID := make(map[uint]models.Translation, len(translations))
+		wordIDs := make([]uint, 0, 2*len(translations))
+		for _, translation := range translations {
+			translationsByID[translation.ID] = translation
+			wordIDs = append(wordIDs, translation.WordID, translation.TargetWordID)
+		}
+
+		var inflections []models.Inflection
+		if err := db.Where("word_id IN ?", wordIDs).Find(&inflections).Error; err != nil {
+			return err
+		}
+		inflectionsByWord := make(map[uint][]models.Inflection)
+		for _, inflection := range inflections {
+			inflectionsByWord[inflection.WordID] = append(inflectionsByWord[inflection.WordID], inflection)
+		}
+
+		for i := range examples {
+			translation, ok := translationsByID[examples[i].TranslationID]
+			if !ok {
+				continue
+			}
+			word := exampleTerm(translation, examples[i])
+			if !usesTerm(examples[i].Sentence.Text, word, inflectionsByWord[word.ID], r.lemmatizerFor(word.LanguageCode)) {
+				violations = append(violations, &model.ExampleViolation{
+					Example:      ToGraphQLExample(&examples[i]),
+					ExpectedTerm: word.Term,
+				})
+			}
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return nil, apperrors.NewInternal(result.Error)
+	}
+	return violations, nil
+}
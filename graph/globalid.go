@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"translatorapi/apperrors"
+)
+
+// Type names used as prefixes of global IDs
+const (
+	wordType        = "Word"
+	translationType = "Translation"
+	exampleType     = "Example"
+	sentenceType    = "Sentence"
+	inflectionType  = "Inflection"
+	revisionType    = "Revision"
+	// Word relations are not nodes, their IDs only identify them in revisions
+	wordRelationType = "WordRelation"
+)
+
+// toGlobalID builds an opaque ID, unique across all types, e.g. "Word:1" encoded in base64
+func toGlobalID(typeName string, id uint) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d", typeName, id)))
+}
+
+// splitGlobalID decodes a global ID into its type name and primary key
+func splitGlobalID(field string, globalID string) (string, uint, error) {
+	raw, err := base64.StdEncoding.DecodeString(globalID)
+	if err != nil {
+		return "", 0, apperrors.NewValidation(field, "invalid id: %s", globalID)
+	}
+
+	typeName, rawID, found := strings.Cut(string(raw), ":")
+	if !found {
+		return "", 0, apperrors.NewValidation(field, "invalid id: %s", globalID)
+	}
+
+	id, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil || id == 0 {
+		return "", 0, apperrors.NewValidation(field, "invalid id: %s", globalID)
+	}
+
+	return typeName, uint(id), nil
+}
+
+// fromGlobalID decodes a global ID which must point at an entity of the expected type
+func fromGlobalID(field string, globalID string, expectedType string) (uint, error) {
+	typeName, id, err := splitGlobalID(field, globalID)
+	if err != nil {
+		return 0, err
+	}
+	if typeName != expectedType {
+		return 0, apperrors.NewValidation(field, "id %s does not belong to a %s", globalID, expectedType)
+	}
+	return id, nil
+}
@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+)
+
+// setGrammar applies the grammatical metadata to the word. Nil arguments leave the current values, an empty note clears it.
+// Gender only applies to nouns and aspect only to verbs, so values left over from another part of speech are dropped.
+func setGrammar(word *models.Word, partOfSpeech *model.PartOfSpeech, gender *model.Gender, aspect *model.Aspect, note *string) error {
+	if partOfSpeech != nil {
+		word.PartOfSpeech = enumString(partOfSpeech)
+	}
+	isNoun := word.PartOfSpeech != nil && *word.PartOfSpeech == model.PartOfSpeechNoun.String()
+	isVerb := word.PartOfSpeech != nil && *word.PartOfSpeech == model.PartOfSpeechVerb.String()
+
+	if gender != nil {
+		if !isNoun {
+			return apperrors.NewValidation("gender", "gender can only be set for nouns")
+		}
+		word.Gender = enumString(gender)
+	}
+	if aspect != nil {
+		if !isVerb {
+			return apperrors.NewValidation("aspect", "aspect can only be set for verbs")
+		}
+		word.Aspect = enumString(aspect)
+	}
+
+	if !isNoun {
+		word.Gender = nil
+	}
+	if !isVerb {
+		word.Aspect = nil
+	}
+
+	if note != nil {
+		word.Note = note
+		if *note == "" {
+			word.Note = nil
+		}
+	}
+
+	return nil
+}
+
+// filterWords narrows a words query to the language and the grammatical categories set in the filter.
+// Words lists have always been Polish, so other languages have to be asked for by filter.languageCode.
+func filterWords(query *gorm.DB, filter *model.WordFilter) *gorm.DB {
+	query = query.Where("language_code = ?", filterLanguage(filter))
+	if filter == nil {
+		return query
+	}
+
+	if filter.Kind != nil {
+		query = query.Where("kind = ?", filter.Kind.String())
+	}
+	if filter.PartOfSpeech != nil {
+		query = query.Where("part_of_speech = ?", filter.PartOfSpeech.String())
+	}
+	if filter.Gender != nil {
+		query = query.Where("gender = ?", filter.Gender.String())
+	}
+	if filter.Aspect != nil {
+		query = query.Where("aspect = ?", filter.Aspect.String())
+	}
+	return query
+}
+
+// filterLanguage returns the language the filter asks for, Polish when it does not say
+func filterLanguage(filter *model.WordFilter) string {
+	if filter == nil {
+		return languagePolish
+	}
+	return languageOrDefault(filter.LanguageCode)
+}
+
+// enumString stores a GraphQL enum value by its name
+func enumString[T ~string](value *T) *string {
+	if value == nil {
+		return nil
+	}
+	s := string(*value)
+	return &s
+}
+
+// enumValue reads back a GraphQL enum value stored by enumString
+func enumValue[T ~string](value *string) *T {
+	if value == nil {
+		return nil
+	}
+	v := T(*value)
+	return &v
+}
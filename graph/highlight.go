@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/lemmatizer"
+	"translatorapi/models"
+	"unicode"
+)
+
+// token is a word of a text, with its offsets in runes; end is exclusive
+type token struct {
+	text       string
+	start, end int
+}
+
+// isWordRune tells whether the rune belongs to a word. Marks are included, so decomposed letters stay whole.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.M, r)
+}
+
+// isApostrophe tells whether the rune joins the parts of a word such as "don't"
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’'
+}
+
+// tokenize splits the text into words. Everything else, including hyphens, separates words.
+func tokenize(text string) []token {
+	runes := []rune(text)
+
+	var tokens []token
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(runes) && (isWordRune(runes[i]) || (isApostrophe(runes[i]) && i+1 < len(runes) && isWordRune(runes[i+1]))) {
+			i++
+		}
+		tokens = append(tokens, token{text: string(runes[start:i]), start: start, end: i})
+	}
+	return tokens
+}
+
+// highlightTerm is a word to look for in a sentence: its headword, its stored inflected forms
+// and the lemmatizer of its language, which recognizes forms missing from the inflection tables.
+type highlightTerm struct {
+	headword string
+	forms    [][]token
+	lem      lemmatizer.Lemmatizer
+}
+
+func newHighlightTerm(word models.Word, inflections []models.Inflection, lem lemmatizer.Lemmatizer) highlightTerm {
+	term := highlightTerm{headword: word.Term, lem: lem}
+
+	for _, form := range append([]string{word.Term}, inflectionForms(inflections)...) {
+		if tokens := tokenize(form); len(tokens) > 0 {
+			term.forms = append(term.forms, tokens)
+		}
+	}
+	return term
+}
+
+func inflectionForms(inflections []models.Inflection) []string {
+	forms := make([]string, 0, len(inflections))
+	for _, inflection := range inflections {
+		forms = append(forms, inflection.Form)
+	}
+	return forms
+}
+
+// matchLength returns how many tokens, starting at tokens[0], form a form of the term; 0 when none does.
+// Multi-word forms such as "ice cream" have to match word by word, single words may also match by their lemma.
+func (t highlightTerm) matchLength(tokens []token) int {
+	longest := 0
+	for _, form := range t.forms {
+		if len(form) <= longest || len(form) > len(tokens) {
+			continue
+		}
+
+		matches := true
+		for i := range form {
+			if !strings.EqualFold(form[i].text, tokens[i].text) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			longest = len(form)
+		}
+	}
+
+	if longest == 0 && t.lem != nil {
+		for _, lemma := range t.lem.Lemmas(tokens[0].text) {
+			if strings.EqualFold(lemma, t.headword) {
+				return 1
+			}
+		}
+	}
+	return longest
+}
+
+// findHighlights returns the spans of the sentence where any of the terms occurs, from left to right.
+// Spans do not overlap; where two forms start at the same word the longer one wins.
+func findHighlights(sentence string, terms []highlightTerm) []*model.Span {
+	runes := []rune(sentence)
+	tokens := tokenize(sentence)
+
+	spans := []*model.Span{}
+	for i := 0; i < len(tokens); {
+		length := 0
+		for _, term := range terms {
+			if l := term.matchLength(tokens[i:]); l > length {
+				length = l
+			}
+		}
+		if length == 0 {
+			i++
+			continue
+		}
+
+		start, end := tokens[i].start, tokens[i+length-1].end
+		spans = append(spans, &model.Span{Start: int32(start), End: int32(end), Text: string(runes[start:end])})
+		i += length
+	}
+	return spans
+}
+
+// highlights finds the words of the example's translation in its sentence.
+// The translation and the inflected forms of both its words come from the request loaders.
+func (r *Resolver) highlights(ctx context.Context, example *model.Example) ([]*model.Span, error) {
+	translationID, err := fromGlobalID("translationID", example.TranslationID, translationType)
+	if err != nil {
+		return nil, err
+	}
+
+	loaders := loadersFor(ctx, r.DB)
+	translation, err := loaders.TranslationByID.Load(translationID)
+	if err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+	// The translation was deleted meanwhile
+	if translation.ID == 0 {
+		return []*model.Span{}, nil
+	}
+
+	terms := make([]highlightTerm, 0, 2)
+	for _, word := range []models.Word{translation.Word, translation.TargetWord} {
+		inflections, err := loaders.InflectionsByWord.Load(word.ID)
+		if err != nil {
+			return nil, apperrors.NewInternal(err)
+		}
+		terms = append(terms, newHighlightTerm(word, inflections, r.lemmatizerFor(word.LanguageCode)))
+	}
+
+	return findHighlights(example.Sentence, terms), nil
+}
@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"testing"
+	"translatorapi/graph/model"
+	"translatorapi/lemmatizer"
+	"translatorapi/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		tokens []token
+	}{
+		{
+			name:   "empty",
+			text:   "",
+			tokens: nil,
+		},
+		{
+			name: "offsets are in runes, not bytes",
+			text: "Zażółć gęślą jaźń.",
+			tokens: []token{
+				{text: "Zażółć", start: 0, end: 6},
+				{text: "gęślą", start: 7, end: 12},
+				{text: "jaźń", start: 13, end: 17},
+			},
+		},
+		{
+			name: "punctuation is not part of a word",
+			text: "(kot), \"pies\"!",
+			tokens: []token{
+				{text: "kot", start: 1, end: 4},
+				{text: "pies", start: 8, end: 12},
+			},
+		},
+		{
+			name: "apostrophes join words, hyphens split them",
+			text: "Don't be well-known'",
+			tokens: []token{
+				{text: "Don't", start: 0, end: 5},
+				{text: "be", start: 6, end: 8},
+				{text: "well", start: 9, end: 13},
+				{text: "known", start: 14, end: 19},
+			},
+		},
+		{
+			name: "decomposed letters stay whole",
+			text: "z\u0307e",
+			tokens: []token{
+				{text: "z\u0307e", start: 0, end: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.tokens, tokenize(tt.text))
+		})
+	}
+}
+
+func TestFindHighlights(t *testing.T) {
+	term := func(headword string, forms ...string) highlightTerm {
+		inflections := make([]models.Inflection, 0, len(forms))
+		for _, form := range forms {
+			inflections = append(inflections, models.Inflection{Form: form})
+		}
+		return newHighlightTerm(models.Word{Term: headword}, inflections, nil)
+	}
+
+	tests := []struct {
+		name     string
+		sentence string
+		terms    []highlightTerm
+		spans    []*model.Span
+	}{
+		{
+			name:     "no match",
+			sentence: "Zjadłem kotlet.",
+			terms:    []highlightTerm{term("kot")},
+			spans:    []*model.Span{},
+		},
+		{
+			name:     "matches at the start and at the end",
+			sentence: "Kot widzi kota",
+			terms:    []highlightTerm{term("kot", "kota")},
+			spans: []*model.Span{
+				{Start: 0, End: 3, Text: "Kot"},
+				{Start: 10, End: 14, Text: "kota"},
+			},
+		},
+		{
+			name:     "punctuation next to a match",
+			sentence: "To mój żółw, a to twój żółw!",
+			terms:    []highlightTerm{term("żółw")},
+			spans: []*model.Span{
+				{Start: 7, End: 11, Text: "żółw"},
+				{Start: 23, End: 27, Text: "żółw"},
+			},
+		},
+		{
+			name:     "multi-byte characters before a match",
+			sentence: "Źdźbło trawy i źdźbła.",
+			terms:    []highlightTerm{term("źdźbło", "źdźbła")},
+			spans: []*model.Span{
+				{Start: 0, End: 6, Text: "Źdźbło"},
+				{Start: 15, End: 21, Text: "źdźbła"},
+			},
+		},
+		{
+			name:     "repeated matches of several terms",
+			sentence: "Kot i pies, pies i kot.",
+			terms:    []highlightTerm{term("kot"), term("pies")},
+			spans: []*model.Span{
+				{Start: 0, End: 3, Text: "Kot"},
+				{Start: 6, End: 10, Text: "pies"},
+				{Start: 12, End: 16, Text: "pies"},
+				{Start: 19, End: 22, Text: "kot"},
+			},
+		},
+		{
+			name:     "the longer form wins",
+			sentence: "Ice cream, not ice.",
+			terms:    []highlightTerm{term("ice"), term("ice cream")},
+			spans: []*model.Span{
+				{Start: 0, End: 9, Text: "Ice cream"},
+				{Start: 15, End: 18, Text: "ice"},
+			},
+		},
+		{
+			name:     "forms missing from the tables match by their lemma",
+			sentence: "Bawię się z kotami.",
+			terms: []highlightTerm{
+				newHighlightTerm(models.Word{Term: "kot"}, nil, lemmatizer.NewRuleBased()),
+			},
+			spans: []*model.Span{
+				{Start: 12, End: 18, Text: "kotami"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.spans, findHighlights(tt.sentence, tt.terms))
+		})
+	}
+}
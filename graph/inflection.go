@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"sort"
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/lemmatizer"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+)
+
+// liveInflections keeps only the inflections of stored words.
+// A deleted word keeps its inflections, they are hidden until the word is restored.
+func liveInflections(db *gorm.DB) *gorm.DB {
+	return db.Where("inflections.word_id IN (?)", db.Model(&models.Word{}).Select("id"))
+}
+
+// setInflectionCategories applies the grammatical categories of an inflected form. Nil arguments leave the current values.
+// A form without any category would not say which cell of the inflection table it fills, so it is rejected.
+func setInflectionCategories(inflection *models.Inflection, grammaticalCase *model.GrammaticalCase, number *model.GrammaticalNumber, person *model.GrammaticalPerson) error {
+	if grammaticalCase != nil {
+		inflection.GrammaticalCase = enumString(grammaticalCase)
+	}
+	if number != nil {
+		inflection.Number = enumString(number)
+	}
+	if person != nil {
+		inflection.Person = enumString(person)
+	}
+
+	if inflection.GrammaticalCase == nil && inflection.Number == nil && inflection.Person == nil {
+		return apperrors.NewValidation("grammaticalCase", "at least one of grammaticalCase, number or person is required")
+	}
+	return nil
+}
+
+// defaultLemmatizer is used by resolvers created without a Lemmatizer
+var defaultLemmatizer lemmatizer.Lemmatizer = lemmatizer.NewRuleBased()
+
+// lemmatizer returns the configured lemmatizer, falling back to the rule-based one
+func (r *Resolver) lemmatizer() lemmatizer.Lemmatizer {
+	if r.Lemmatizer != nil {
+		return r.Lemmatizer
+	}
+	return defaultLemmatizer
+}
+
+// lemmatizerFor returns the lemmatizer of the language, nil for languages without one.
+// The lemmatizers only know Polish endings.
+func (r *Resolver) lemmatizerFor(languageCode string) lemmatizer.Lemmatizer {
+	if languageCode != languagePolish {
+		return nil
+	}
+	return r.lemmatizer()
+}
+
+// lemmaMatch is a word found for the text of a lookup.
+// Inflection is the inflected form the word was found by, nil when the text is the headword.
+// Lemmatized is set when the word was only guessed by the lemmatizer.
+type lemmaMatch struct {
+	Word       models.Word
+	Inflection *models.Inflection
+	Lemmatized bool
+}
+
+// findLemmas resolves the text to the words it is a form of.
+// A headword wins over inflected forms; otherwise every word having the text as an inflected form is returned,
+// since different words may share a form (e.g. "mam" of both "mieć" and "mama").
+// Only when the inflection tables know nothing about the text, the lemmatizer guesses its lemmas; a nil lem skips guessing.
+// Only words of the given language are considered.
+func findLemmas(db *gorm.DB, lem lemmatizer.Lemmatizer, text string, languageCode string) ([]lemmaMatch, error) {
+	var word models.Word
+	err := db.Where("language_code = ? AND term = ?", languageCode, text).First(&word).Error
+	if err == nil {
+		return []lemmaMatch{{Word: word}}, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	var inflections []models.Inflection
+	if err := db.Joins("Word").Where(`inflections.form = ? AND "Word".language_code = ?`, text, languageCode).Order("inflections.word_id, inflections.id").Find(&inflections).Error; err != nil {
+		return nil, err
+	}
+
+	matches := make([]lemmaMatch, 0, len(inflections))
+	for i := range inflections {
+		// The same form may fill several cells of one word, the word is returned once
+		if len(matches) > 0 && matches[len(matches)-1].Word.ID == inflections[i].WordID {
+			continue
+		}
+		matches = append(matches, lemmaMatch{Word: inflections[i].Word, Inflection: &inflections[i]})
+	}
+	if len(matches) > 0 {
+		return matches, nil
+	}
+
+	if lem == nil {
+		return nil, nil
+	}
+	return guessLemmas(db, lem, text, languageCode)
+}
+
+// guessLemmas checks the lemmas guessed by the lemmatizer against the stored headwords.
+// Matching words keep the order of the candidates, so the most likely lemma comes first.
+func guessLemmas(db *gorm.DB, lem lemmatizer.Lemmatizer, text string, languageCode string) ([]lemmaMatch, error) {
+	candidates := lem.Lemmas(text)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	var words []models.Word
+	if err := db.Where("language_code = ? AND term IN ?", languageCode, candidates).Find(&words).Error; err != nil {
+		return nil, err
+	}
+
+	rank := make(map[string]int, len(candidates))
+	for i, c := range candidates {
+		rank[c] = i
+	}
+	sort.Slice(words, func(i, j int) bool {
+		return rank[words[i].Term] < rank[words[j].Term]
+	})
+
+	matches := make([]lemmaMatch, 0, len(words))
+	for _, word := range words {
+		matches = append(matches, lemmaMatch{Word: word, Lemmatized: true})
+	}
+	return matches, nil
+}
+
+// formMatch reports how the text of a lookup was matched to the word
+func formMatch(text string, match lemmaMatch) *model.FormMatch {
+	if match.Lemmatized {
+		return &model.FormMatch{Form: text, Source: model.FormMatchSourceLemmatizer}
+	}
+	if match.Inflection == nil {
+		return &model.FormMatch{Form: text, Source: model.FormMatchSourceHeadword}
+	}
+	return &model.FormMatch{
+		Form:       text,
+		Source:     model.FormMatchSourceInflection,
+		Inflection: ToGraphQLInflection(match.Inflection),
+	}
+}
@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"strings"
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxDomainLength matches the size of translations.domain
+const maxDomainLength = 64
+
+// setLabels applies the usage labels to the translation. Nil arguments leave the current values, an empty domain clears it.
+func setLabels(translation *models.Translation, register *model.Register, domain *string, region *model.Region) error {
+	if register != nil {
+		translation.Register = enumString(register)
+	}
+	if region != nil {
+		translation.Region = enumString(region)
+	}
+
+	if domain != nil {
+		trimmed := strings.TrimSpace(*domain)
+		if len([]rune(trimmed)) > maxDomainLength {
+			return apperrors.NewValidation("domain", "domain must be at most %d characters long", maxDomainLength)
+		}
+		translation.Domain = &trimmed
+		if trimmed == "" {
+			translation.Domain = nil
+		}
+	}
+
+	return nil
+}
+
+// labelFilter narrows translations to the given usage labels, nil fields match every translation
+type labelFilter struct {
+	Register *model.Register
+	Domain   *string
+	Region   *model.Region
+}
+
+// apply adds the filter to a translations query. Domains are compared case-insensitively, so "IT" matches "it".
+func (f labelFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.Register != nil {
+		query = query.Where("translations.register = ?", f.Register.String())
+	}
+	if f.Domain != nil {
+		query = query.Where("LOWER(translations.domain) = LOWER(?)", strings.TrimSpace(*f.Domain))
+	}
+	if f.Region != nil {
+		query = query.Where("translations.region = ?", f.Region.String())
+	}
+	return query
+}
+
+// saveLabels writes the usage labels of the translation, including cleared ones, and refreshes its updatedAt
+func saveLabels(tx *gorm.DB, translation *models.Translation) error {
+	err := tx.Model(translation).Omit(clause.Associations).Updates(map[string]interface{}{
+		"register": translation.Register,
+		"domain":   translation.Domain,
+		"region":   translation.Region,
+	}).Error
+	if err != nil {
+		return apperrors.NewInternal(err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package graph
+
+import (
+	"regexp"
+	"translatorapi/apperrors"
+)
+
+const (
+	// languagePolish and languageEnglish are the languages of the Polish-English wrappers
+	languagePolish  = "pl"
+	languageEnglish = "en"
+)
+
+// languageCodePattern accepts ISO 639-1 codes such as "pl" and the three-letter ISO 639-3 ones
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// validateLanguage rejects language codes which are not lowercase ISO 639 codes
+func validateLanguage(field string, code string) error {
+	if !languageCodePattern.MatchString(code) {
+		return apperrors.NewValidation(field, "invalid language code: %q", code)
+	}
+	return nil
+}
+
+// languageOrDefault returns the language code, falling back to Polish when it is not given
+func languageOrDefault(code *string) string {
+	if code == nil {
+		return languagePolish
+	}
+	return *code
+}
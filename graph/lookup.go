@@ -0,0 +1,369 @@
+package graph
+
+import (
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/lemmatizer"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// findOrCreateTerm returns the word spelled term in the language, inserting it when it is not stored yet,
+// and tells whether it was inserted.
+// ON CONFLICT DO NOTHING lets concurrent transactions share the same word instead of failing.
+// Deleted words do not count, the unique index only covers the stored ones.
+func findOrCreateTerm(tx *gorm.DB, term string, languageCode string) (models.Word, bool, error) {
+	word := models.Word{Term: term, LanguageCode: languageCode}
+
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "language_code"}, {Name: "term"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(&word).Error; err != nil {
+		return word, false, err
+	}
+
+	// Word already existed, so nothing was inserted and the ID is still empty
+	if word.ID == 0 {
+		if err := tx.Where("language_code = ? AND term = ?", languageCode, term).First(&word).Error; err != nil {
+			return word, false, err
+		}
+		return word, false, nil
+	}
+
+	return word, true, nil
+}
+
+// createTerm inserts a new word with its grammatical metadata and entry kind, the default kind when nil.
+// An existing word with the same term and language is reported as ALREADY_EXISTS on termField.
+func createTerm(tx *gorm.DB, termField string, term string, languageCode string, partOfSpeech *model.PartOfSpeech, gender *model.Gender, aspect *model.Aspect, note *string, kind *model.EntryKind) (models.Word, error) {
+	word := models.Word{Term: term, LanguageCode: languageCode}
+	if err := setGrammar(&word, partOfSpeech, gender, aspect, note); err != nil {
+		return word, err
+	}
+	if err := setKind(tx, &word, kind); err != nil {
+		return word, err
+	}
+
+	result := tx.Where("language_code = ? AND term = ?", languageCode, term).FirstOrCreate(&word)
+
+	// If there was an error
+	if result.Error != nil {
+		return word, apperrors.FromDB(result.Error, termField, "word already exists: %s", term)
+	}
+
+	// No error, check if the word was created or already existed
+	if result.RowsAffected == 0 {
+		// No rows were affected, meaning the word already existed
+		return word, apperrors.NewAlreadyExists(termField, "word already exists: %s", term)
+	}
+
+	return word, nil
+}
+
+// findTerm looks up the word spelled term in the language.
+// A missing word is reported as NOT_FOUND on the field, with suggestions from the same language.
+func findTerm(tx *gorm.DB, field string, what string, term string, languageCode string) (models.Word, error) {
+	var word models.Word
+	if err := tx.Where("language_code = ? AND term = ?", languageCode, term).First(&word).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return word, notFoundError(tx, field, what, term, languageCode)
+		}
+		return word, apperrors.NewInternal(err)
+	}
+	return word, nil
+}
+
+// withTerms joins both words of the translations, so their terms and languages can be shown
+func withTerms(tx *gorm.DB) *gorm.DB {
+	return tx.Joins("Word").Joins("TargetWord")
+}
+
+// findTranslation looks up the translation of the word into the given term of the target language.
+// It returns gorm.ErrRecordNotFound when the word is not translated by that term.
+func findTranslation(tx *gorm.DB, wordID uint, targetTerm string, targetLanguage string) (models.Translation, error) {
+	var translation models.Translation
+	err := withTerms(tx).
+		Where(`translations.word_id = ? AND "TargetWord".term = ? AND "TargetWord".language_code = ?`, wordID, targetTerm, targetLanguage).
+		First(&translation).Error
+
+	return translation, err
+}
+
+// addTranslation links the word to the target term, adding the term when it is not stored yet,
+// and optionally gives the new translation an example sentence. An added term is recorded in log.
+// termField and languageField name the arguments the target came from, for error reporting.
+// The example sentence is checked by validate.
+func addTranslation(tx *gorm.DB, log *revisionLog, word models.Word, targetTerm string, targetLanguage string, sentence *string, termField string, languageField string, validate exampleValidator) (models.Translation, error) {
+	var translation models.Translation
+
+	if targetLanguage == word.LanguageCode {
+		return translation, apperrors.NewValidation(languageField, "a word cannot be translated into its own language: %s", targetLanguage)
+	}
+
+	target, created, err := findOrCreateTerm(tx, targetTerm, targetLanguage)
+	if err != nil {
+		return translation, apperrors.NewInternal(err)
+	}
+	if created {
+		log.created(model.RevisionEntityWord, target.ID)
+	}
+
+	translation = models.Translation{
+		WordID:       word.ID,
+		TargetWordID: target.ID,
+	}
+
+	// A new translation is ranked after the existing meanings of the word
+	rank, err := nextRank(tx, word.ID)
+	if err != nil {
+		return translation, apperrors.NewInternal(err)
+	}
+
+	result := tx.Where(&translation).Attrs(models.Translation{Rank: rank}).FirstOrCreate(&translation)
+
+	// If there was an error
+	if result.Error != nil {
+		return translation, apperrors.FromDB(result.Error, termField, "translation already exists: %s", targetTerm)
+	}
+
+	// No error, check if the translation was created or already existed
+	if result.RowsAffected == 0 {
+		// No rows were affected, meaning the translation already existed
+		return translation, apperrors.NewAlreadyExists(termField, "translation already exists: %s", targetTerm)
+	}
+	translation.Word = word
+	translation.TargetWord = target
+
+	if sentence != nil {
+		exampleSentence, err := findOrCreateSentence(tx, *sentence)
+		if err != nil {
+			return translation, apperrors.NewInternal(err)
+		}
+
+		example := models.Example{
+			TranslationID: translation.ID,
+			SentenceID:    exampleSentence.ID,
+			Sentence:      exampleSentence,
+			LanguageCode:  word.LanguageCode,
+		}
+		if err := validate(tx, translation, &example); err != nil {
+			return translation, err
+		}
+
+		// The sentence is already stored, so associations are not saved again
+		if err := tx.Omit(clause.Associations).Create(&example).Error; err != nil {
+			return translation, apperrors.FromDB(err, "sentence", "example already exists: %s", *sentence)
+		}
+	}
+
+	return translation, nil
+}
+
+// lookupTranslations finds the translations of the term from the source language,
+// into targetLanguage or into every language when it is nil.
+// The term may be an inflected form, resolved to its words by findLemmas, and matchedForm tells which form matched.
+// labels keeps only the translations with the given usage labels.
+func lookupTranslations(db *gorm.DB, lem lemmatizer.Lemmatizer, field string, term string, sourceLanguage string, targetLanguage *string, labels labelFilter) ([]*model.Translation, error) {
+	matches, err := findLemmas(db, lem, term, sourceLanguage)
+	if err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+	if len(matches) == 0 {
+		return nil, notFoundError(db, field, "word", term, sourceLanguage)
+	}
+
+	var gqlTranslations []*model.Translation
+	for _, match := range matches {
+		query := withTerms(db).Where("translations.word_id = ?", match.Word.ID)
+		if targetLanguage != nil {
+			query = query.Where(`"TargetWord".language_code = ?`, *targetLanguage)
+		}
+		query = labels.apply(query)
+
+		var translations []*models.Translation
+		if err := query.Order(rankOrder).Find(&translations).Error; err != nil {
+			return nil, apperrors.NewInternal(err)
+		}
+
+		matchedForm := formMatch(term, match)
+		for _, translation := range translations {
+			gqlTranslation := ToGraphQLTranslation(translation)
+			gqlTranslation.MatchedForm = matchedForm
+			gqlTranslations = append(gqlTranslations, gqlTranslation)
+		}
+	}
+
+	return gqlTranslations, nil
+}
+
+// findReverseTranslations finds the translations leading to the target word,
+// from sourceLanguage or from every language when it is nil.
+func findReverseTranslations(db *gorm.DB, targetWordID uint, sourceLanguage *string) ([]*models.Translation, error) {
+	query := withTerms(db).Where("translations.target_word_id = ?", targetWordID)
+	if sourceLanguage != nil {
+		query = query.Where(`"Word".language_code = ?`, *sourceLanguage)
+	}
+
+	var translations []*models.Translation
+	err := query.Order("translations.id").Find(&translations).Error
+	return translations, err
+}
+
+// findOrCreateSentence returns the sentence, inserting it when it is not stored yet.
+// Examples of different translations reuse the same sentence row.
+func findOrCreateSentence(tx *gorm.DB, text string) (models.Sentence, error) {
+	sentence := models.Sentence{Text: text}
+
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "text"}},
+		DoNothing: true,
+	}).Create(&sentence).Error; err != nil {
+		return sentence, err
+	}
+
+	// Sentence already existed, so nothing was inserted and the ID is still empty
+	if sentence.ID == 0 {
+		if err := tx.Where("text = ?", text).First(&sentence).Error; err != nil {
+			return sentence, err
+		}
+	}
+
+	return sentence, nil
+}
+
+// withSentences joins the sentence of the examples and its parallel sentence, which may be missing
+func withSentences(tx *gorm.DB) *gorm.DB {
+	return tx.Joins("Sentence").Joins("ParallelSentence")
+}
+
+// findExample looks up the example of the translation with the given sentence.
+// It returns gorm.ErrRecordNotFound when the translation has no such example.
+func findExample(tx *gorm.DB, translationID uint, sentence string) (models.Example, error) {
+	var example models.Example
+	err := withSentences(tx).
+		Where(`examples.translation_id = ? AND "Sentence".text = ?`, translationID, sentence).
+		First(&example).Error
+
+	return example, err
+}
+
+// findWordByKey finds a word either by its global ID or by its Polish spelling.
+// Exactly one of the keys has to be given; idField names the ID argument for errors.
+func findWordByKey(tx *gorm.DB, idField string, id *string, polishWord *string) (models.Word, error) {
+	return findWordByTermKey(tx, idField, id, "polishWord", polishWord, languagePolish)
+}
+
+// findWordByTermKey finds a word either by its global ID or by its term in the given language.
+// Exactly one of the keys has to be given; idField and termField name the arguments for errors.
+func findWordByTermKey(tx *gorm.DB, idField string, id *string, termField string, term *string, languageCode string) (models.Word, error) {
+	var word models.Word
+
+	switch {
+	case id != nil && term != nil:
+		return word, apperrors.NewValidation(idField, "provide either %s or %s, not both", idField, termField)
+	case id != nil:
+		wordID, err := fromGlobalID(idField, *id, wordType)
+		if err != nil {
+			return word, err
+		}
+		if err := tx.First(&word, wordID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return word, apperrors.NewNotFound(idField, "word not found: %s", *id)
+			}
+			return word, apperrors.NewInternal(err)
+		}
+	case term != nil:
+		return findTerm(tx, termField, "word", *term, languageCode)
+	default:
+		return word, apperrors.NewValidation(termField, "either %s or %s is required", idField, termField)
+	}
+
+	return word, nil
+}
+
+// findTranslationByKey finds a translation either by its global ID or by the Polish and English words.
+func findTranslationByKey(tx *gorm.DB, idField string, id *string, polishWord *string, englishWord *string) (models.Translation, error) {
+	var translation models.Translation
+
+	if id != nil {
+		if polishWord != nil || englishWord != nil {
+			return translation, apperrors.NewValidation(idField, "provide either %s or polishWord and englishWord, not both", idField)
+		}
+
+		translationID, err := fromGlobalID(idField, *id, translationType)
+		if err != nil {
+			return translation, err
+		}
+		if err := withTerms(tx).First(&translation, "translations.id = ?", translationID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return translation, apperrors.NewNotFound(idField, "translation not found: %s", *id)
+			}
+			return translation, apperrors.NewInternal(err)
+		}
+		return translation, nil
+	}
+
+	if englishWord == nil {
+		return translation, apperrors.NewValidation("englishWord", "either %s or polishWord and englishWord are required", idField)
+	}
+
+	word, err := findWordByKey(tx, idField, nil, polishWord)
+	if err != nil {
+		return translation, err
+	}
+
+	translation, err = findTranslation(tx, word.ID, *englishWord, languageEnglish)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return translation, notFoundError(tx, "englishWord", "translation", *englishWord, languageEnglish)
+		}
+		return translation, apperrors.NewInternal(err)
+	}
+
+	return translation, nil
+}
+
+// findExampleByKey finds an example either by its global ID or by the words and the sentence.
+func findExampleByKey(tx *gorm.DB, idField string, id *string, polishWord *string, englishWord *string, sentence *string) (models.Example, error) {
+	var example models.Example
+
+	if id != nil {
+		if polishWord != nil || englishWord != nil || sentence != nil {
+			return example, apperrors.NewValidation(idField, "provide either %s or polishWord, englishWord and exampleSentence, not both", idField)
+		}
+
+		exampleID, err := fromGlobalID(idField, *id, exampleType)
+		if err != nil {
+			return example, err
+		}
+		if err := withSentences(tx).First(&example, "examples.id = ?", exampleID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return example, apperrors.NewNotFound(idField, "example not found: %s", *id)
+			}
+			return example, apperrors.NewInternal(err)
+		}
+		return example, nil
+	}
+
+	if sentence == nil {
+		return example, apperrors.NewValidation("exampleSentence", "either %s or polishWord, englishWord and exampleSentence are required", idField)
+	}
+
+	translation, err := findTranslationByKey(tx, idField, nil, polishWord, englishWord)
+	if err != nil {
+		return example, err
+	}
+
+	example, err = findExample(tx, translation.ID, *sentence)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return example, apperrors.NewNotFound("exampleSentence", "example not found: %s", *sentence)
+		}
+		return example, apperrors.NewInternal(err)
+	}
+
+	return example, nil
+}
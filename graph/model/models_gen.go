@@ -2,27 +2,898 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type Node interface {
+	IsNode()
+	GetID() string
+}
+
 type Example struct {
-	ID            string `json:"id"`
-	TranslationID string `json:"translationID"`
-	Sentence      string `json:"sentence"`
+	ID                   string    `json:"id"`
+	TranslationID        string    `json:"translationID"`
+	SentenceID           string    `json:"sentenceID"`
+	Sentence             string    `json:"sentence"`
+	LanguageCode         string    `json:"languageCode"`
+	ParallelSentenceID   *string   `json:"parallelSentenceID,omitempty"`
+	ParallelSentence     *string   `json:"parallelSentence,omitempty"`
+	ParallelLanguageCode *string   `json:"parallelLanguageCode,omitempty"`
+	Flagged              bool      `json:"flagged"`
+	CreatedAt            time.Time `json:"createdAt"`
+	UpdatedAt            time.Time `json:"updatedAt"`
+	CreatedBy            *string   `json:"createdBy,omitempty"`
+	Version              int32     `json:"version"`
+}
+
+func (Example) IsNode()            {}
+func (this Example) GetID() string { return this.ID }
+
+type ExampleViolation struct {
+	Example      *Example `json:"example"`
+	ExpectedTerm string   `json:"expectedTerm"`
+}
+
+type FormMatch struct {
+	Form       string          `json:"form"`
+	Source     FormMatchSource `json:"source"`
+	Inflection *Inflection     `json:"inflection,omitempty"`
 }
 
+type Inflection struct {
+	ID              string             `json:"id"`
+	WordID          string             `json:"wordID"`
+	Form            string             `json:"form"`
+	GrammaticalCase *GrammaticalCase   `json:"grammaticalCase,omitempty"`
+	Number          *GrammaticalNumber `json:"number,omitempty"`
+	Person          *GrammaticalPerson `json:"person,omitempty"`
+	Version         int32              `json:"version"`
+}
+
+func (Inflection) IsNode()            {}
+func (this Inflection) GetID() string { return this.ID }
+
 type Mutation struct {
 }
 
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
+type PolishTranslation struct {
+	ID          string `json:"id"`
+	WordID      string `json:"wordID"`
+	PolishWord  string `json:"polishWord"`
+	EnglishWord string `json:"englishWord"`
+}
+
 type Query struct {
 }
 
+type Revision struct {
+	ID         string         `json:"id"`
+	WordID     string         `json:"wordID"`
+	EntityID   string         `json:"entityID"`
+	EntityType RevisionEntity `json:"entityType"`
+	Action     RevisionAction `json:"action"`
+	Mutation   string         `json:"mutation"`
+	Before     *string        `json:"before,omitempty"`
+	After      *string        `json:"after,omitempty"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	CreatedBy  *string        `json:"createdBy,omitempty"`
+}
+
+func (Revision) IsNode()            {}
+func (this Revision) GetID() string { return this.ID }
+
+type Span struct {
+	Start int32  `json:"start"`
+	End   int32  `json:"end"`
+	Text  string `json:"text"`
+}
+
+type Suggestion struct {
+	Word         string  `json:"word"`
+	LanguageCode string  `json:"languageCode"`
+	Similarity   float64 `json:"similarity"`
+}
+
 type Translation struct {
-	ID          string     `json:"id"`
-	WordID      string     `json:"wordID"`
-	EnglishWord string     `json:"englishWord"`
-	Examples    []*Example `json:"examples"`
+	ID             string     `json:"id"`
+	WordID         string     `json:"wordID"`
+	TargetWordID   string     `json:"targetWordID"`
+	SourceTerm     string     `json:"sourceTerm"`
+	SourceLanguage string     `json:"sourceLanguage"`
+	TargetTerm     string     `json:"targetTerm"`
+	TargetLanguage string     `json:"targetLanguage"`
+	EnglishWord    string     `json:"englishWord"`
+	Rank           int32      `json:"rank"`
+	Register       *Register  `json:"register,omitempty"`
+	Domain         *string    `json:"domain,omitempty"`
+	Region         *Region    `json:"region,omitempty"`
+	MatchedForm    *FormMatch `json:"matchedForm,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+	CreatedBy      *string    `json:"createdBy,omitempty"`
+	Version        int32      `json:"version"`
+}
+
+func (Translation) IsNode()            {}
+func (this Translation) GetID() string { return this.ID }
+
+type TrashItem struct {
+	Node      Node      `json:"node"`
+	DeletedAt time.Time `json:"deletedAt"`
 }
 
 type Word struct {
-	ID           string         `json:"id"`
-	PolishWord   string         `json:"polishWord"`
-	Translations []*Translation `json:"translations"`
+	ID           string        `json:"id"`
+	Term         string        `json:"term"`
+	LanguageCode string        `json:"languageCode"`
+	PolishWord   string        `json:"polishWord"`
+	Kind         EntryKind     `json:"kind"`
+	PartOfSpeech *PartOfSpeech `json:"partOfSpeech,omitempty"`
+	Gender       *Gender       `json:"gender,omitempty"`
+	Aspect       *Aspect       `json:"aspect,omitempty"`
+	Note         *string       `json:"note,omitempty"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+	CreatedBy    *string       `json:"createdBy,omitempty"`
+	Version      int32         `json:"version"`
+}
+
+func (Word) IsNode()            {}
+func (this Word) GetID() string { return this.ID }
+
+type WordConnection struct {
+	Edges    []*WordEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+type WordEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Word  `json:"node"`
+}
+
+type WordFilter struct {
+	LanguageCode *string       `json:"languageCode,omitempty"`
+	Kind         *EntryKind    `json:"kind,omitempty"`
+	PartOfSpeech *PartOfSpeech `json:"partOfSpeech,omitempty"`
+	Gender       *Gender       `json:"gender,omitempty"`
+	Aspect       *Aspect       `json:"aspect,omitempty"`
+}
+
+type WordRelation struct {
+	Type    RelationType `json:"type"`
+	Word    *Word        `json:"word"`
+	Inverse bool         `json:"inverse"`
+}
+
+type Aspect string
+
+const (
+	AspectImperfective Aspect = "IMPERFECTIVE"
+	AspectPerfective   Aspect = "PERFECTIVE"
+)
+
+var AllAspect = []Aspect{
+	AspectImperfective,
+	AspectPerfective,
+}
+
+func (e Aspect) IsValid() bool {
+	switch e {
+	case AspectImperfective, AspectPerfective:
+		return true
+	}
+	return false
+}
+
+func (e Aspect) String() string {
+	return string(e)
+}
+
+func (e *Aspect) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Aspect(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Aspect", str)
+	}
+	return nil
+}
+
+func (e Aspect) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type EntryKind string
+
+const (
+	EntryKindWord        EntryKind = "WORD"
+	EntryKindPhrase      EntryKind = "PHRASE"
+	EntryKindIdiom       EntryKind = "IDIOM"
+	EntryKindCollocation EntryKind = "COLLOCATION"
+)
+
+var AllEntryKind = []EntryKind{
+	EntryKindWord,
+	EntryKindPhrase,
+	EntryKindIdiom,
+	EntryKindCollocation,
+}
+
+func (e EntryKind) IsValid() bool {
+	switch e {
+	case EntryKindWord, EntryKindPhrase, EntryKindIdiom, EntryKindCollocation:
+		return true
+	}
+	return false
+}
+
+func (e EntryKind) String() string {
+	return string(e)
+}
+
+func (e *EntryKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EntryKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EntryKind", str)
+	}
+	return nil
+}
+
+func (e EntryKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ExampleValidation string
+
+const (
+	ExampleValidationOff    ExampleValidation = "OFF"
+	ExampleValidationFlag   ExampleValidation = "FLAG"
+	ExampleValidationReject ExampleValidation = "REJECT"
+)
+
+var AllExampleValidation = []ExampleValidation{
+	ExampleValidationOff,
+	ExampleValidationFlag,
+	ExampleValidationReject,
+}
+
+func (e ExampleValidation) IsValid() bool {
+	switch e {
+	case ExampleValidationOff, ExampleValidationFlag, ExampleValidationReject:
+		return true
+	}
+	return false
+}
+
+func (e ExampleValidation) String() string {
+	return string(e)
+}
+
+func (e *ExampleValidation) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ExampleValidation(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ExampleValidation", str)
+	}
+	return nil
+}
+
+func (e ExampleValidation) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type FormMatchSource string
+
+const (
+	FormMatchSourceHeadword   FormMatchSource = "HEADWORD"
+	FormMatchSourceInflection FormMatchSource = "INFLECTION"
+	FormMatchSourceLemmatizer FormMatchSource = "LEMMATIZER"
+)
+
+var AllFormMatchSource = []FormMatchSource{
+	FormMatchSourceHeadword,
+	FormMatchSourceInflection,
+	FormMatchSourceLemmatizer,
+}
+
+func (e FormMatchSource) IsValid() bool {
+	switch e {
+	case FormMatchSourceHeadword, FormMatchSourceInflection, FormMatchSourceLemmatizer:
+		return true
+	}
+	return false
+}
+
+func (e FormMatchSource) String() string {
+	return string(e)
+}
+
+func (e *FormMatchSource) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FormMatchSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FormMatchSource", str)
+	}
+	return nil
+}
+
+func (e FormMatchSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Gender string
+
+const (
+	GenderMasculinePersonal  Gender = "MASCULINE_PERSONAL"
+	GenderMasculineAnimate   Gender = "MASCULINE_ANIMATE"
+	GenderMasculineInanimate Gender = "MASCULINE_INANIMATE"
+	GenderFeminine           Gender = "FEMININE"
+	GenderNeuter             Gender = "NEUTER"
+)
+
+var AllGender = []Gender{
+	GenderMasculinePersonal,
+	GenderMasculineAnimate,
+	GenderMasculineInanimate,
+	GenderFeminine,
+	GenderNeuter,
+}
+
+func (e Gender) IsValid() bool {
+	switch e {
+	case GenderMasculinePersonal, GenderMasculineAnimate, GenderMasculineInanimate, GenderFeminine, GenderNeuter:
+		return true
+	}
+	return false
+}
+
+func (e Gender) String() string {
+	return string(e)
+}
+
+func (e *Gender) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Gender(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Gender", str)
+	}
+	return nil
+}
+
+func (e Gender) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type GrammaticalCase string
+
+const (
+	GrammaticalCaseNominative   GrammaticalCase = "NOMINATIVE"
+	GrammaticalCaseGenitive     GrammaticalCase = "GENITIVE"
+	GrammaticalCaseDative       GrammaticalCase = "DATIVE"
+	GrammaticalCaseAccusative   GrammaticalCase = "ACCUSATIVE"
+	GrammaticalCaseInstrumental GrammaticalCase = "INSTRUMENTAL"
+	GrammaticalCaseLocative     GrammaticalCase = "LOCATIVE"
+	GrammaticalCaseVocative     GrammaticalCase = "VOCATIVE"
+)
+
+var AllGrammaticalCase = []GrammaticalCase{
+	GrammaticalCaseNominative,
+	GrammaticalCaseGenitive,
+	GrammaticalCaseDative,
+	GrammaticalCaseAccusative,
+	GrammaticalCaseInstrumental,
+	GrammaticalCaseLocative,
+	GrammaticalCaseVocative,
+}
+
+func (e GrammaticalCase) IsValid() bool {
+	switch e {
+	case GrammaticalCaseNominative, GrammaticalCaseGenitive, GrammaticalCaseDative, GrammaticalCaseAccusative, GrammaticalCaseInstrumental, GrammaticalCaseLocative, GrammaticalCaseVocative:
+		return true
+	}
+	return false
+}
+
+func (e GrammaticalCase) String() string {
+	return string(e)
+}
+
+func (e *GrammaticalCase) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GrammaticalCase(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GrammaticalCase", str)
+	}
+	return nil
+}
+
+func (e GrammaticalCase) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type GrammaticalNumber string
+
+const (
+	GrammaticalNumberSingular GrammaticalNumber = "SINGULAR"
+	GrammaticalNumberPlural   GrammaticalNumber = "PLURAL"
+)
+
+var AllGrammaticalNumber = []GrammaticalNumber{
+	GrammaticalNumberSingular,
+	GrammaticalNumberPlural,
+}
+
+func (e GrammaticalNumber) IsValid() bool {
+	switch e {
+	case GrammaticalNumberSingular, GrammaticalNumberPlural:
+		return true
+	}
+	return false
+}
+
+func (e GrammaticalNumber) String() string {
+	return string(e)
+}
+
+func (e *GrammaticalNumber) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GrammaticalNumber(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GrammaticalNumber", str)
+	}
+	return nil
+}
+
+func (e GrammaticalNumber) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type GrammaticalPerson string
+
+const (
+	GrammaticalPersonFirst  GrammaticalPerson = "FIRST"
+	GrammaticalPersonSecond GrammaticalPerson = "SECOND"
+	GrammaticalPersonThird  GrammaticalPerson = "THIRD"
+)
+
+var AllGrammaticalPerson = []GrammaticalPerson{
+	GrammaticalPersonFirst,
+	GrammaticalPersonSecond,
+	GrammaticalPersonThird,
+}
+
+func (e GrammaticalPerson) IsValid() bool {
+	switch e {
+	case GrammaticalPersonFirst, GrammaticalPersonSecond, GrammaticalPersonThird:
+		return true
+	}
+	return false
+}
+
+func (e GrammaticalPerson) String() string {
+	return string(e)
+}
+
+func (e *GrammaticalPerson) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GrammaticalPerson(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GrammaticalPerson", str)
+	}
+	return nil
+}
+
+func (e GrammaticalPerson) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PartOfSpeech string
+
+const (
+	PartOfSpeechNoun         PartOfSpeech = "NOUN"
+	PartOfSpeechVerb         PartOfSpeech = "VERB"
+	PartOfSpeechAdjective    PartOfSpeech = "ADJECTIVE"
+	PartOfSpeechAdverb       PartOfSpeech = "ADVERB"
+	PartOfSpeechPronoun      PartOfSpeech = "PRONOUN"
+	PartOfSpeechNumeral      PartOfSpeech = "NUMERAL"
+	PartOfSpeechPreposition  PartOfSpeech = "PREPOSITION"
+	PartOfSpeechConjunction  PartOfSpeech = "CONJUNCTION"
+	PartOfSpeechParticle     PartOfSpeech = "PARTICLE"
+	PartOfSpeechInterjection PartOfSpeech = "INTERJECTION"
+)
+
+var AllPartOfSpeech = []PartOfSpeech{
+	PartOfSpeechNoun,
+	PartOfSpeechVerb,
+	PartOfSpeechAdjective,
+	PartOfSpeechAdverb,
+	PartOfSpeechPronoun,
+	PartOfSpeechNumeral,
+	PartOfSpeechPreposition,
+	PartOfSpeechConjunction,
+	PartOfSpeechParticle,
+	PartOfSpeechInterjection,
+}
+
+func (e PartOfSpeech) IsValid() bool {
+	switch e {
+	case PartOfSpeechNoun, PartOfSpeechVerb, PartOfSpeechAdjective, PartOfSpeechAdverb, PartOfSpeechPronoun, PartOfSpeechNumeral, PartOfSpeechPreposition, PartOfSpeechConjunction, PartOfSpeechParticle, PartOfSpeechInterjection:
+		return true
+	}
+	return false
+}
+
+func (e PartOfSpeech) String() string {
+	return string(e)
+}
+
+func (e *PartOfSpeech) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PartOfSpeech(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PartOfSpeech", str)
+	}
+	return nil
+}
+
+func (e PartOfSpeech) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Region string
+
+const (
+	RegionUk Region = "UK"
+	RegionUs Region = "US"
+)
+
+var AllRegion = []Region{
+	RegionUk,
+	RegionUs,
+}
+
+func (e Region) IsValid() bool {
+	switch e {
+	case RegionUk, RegionUs:
+		return true
+	}
+	return false
+}
+
+func (e Region) String() string {
+	return string(e)
+}
+
+func (e *Region) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Region(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Region", str)
+	}
+	return nil
+}
+
+func (e Region) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Register string
+
+const (
+	RegisterFormal     Register = "FORMAL"
+	RegisterColloquial Register = "COLLOQUIAL"
+	RegisterVulgar     Register = "VULGAR"
+	RegisterArchaic    Register = "ARCHAIC"
+)
+
+var AllRegister = []Register{
+	RegisterFormal,
+	RegisterColloquial,
+	RegisterVulgar,
+	RegisterArchaic,
+}
+
+func (e Register) IsValid() bool {
+	switch e {
+	case RegisterFormal, RegisterColloquial, RegisterVulgar, RegisterArchaic:
+		return true
+	}
+	return false
+}
+
+func (e Register) String() string {
+	return string(e)
+}
+
+func (e *Register) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Register(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Register", str)
+	}
+	return nil
+}
+
+func (e Register) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type RelationType string
+
+const (
+	RelationTypeSynonym     RelationType = "SYNONYM"
+	RelationTypeAntonym     RelationType = "ANTONYM"
+	RelationTypeHypernym    RelationType = "HYPERNYM"
+	RelationTypeDiminutive  RelationType = "DIMINUTIVE"
+	RelationTypeDerivedFrom RelationType = "DERIVED_FROM"
+)
+
+var AllRelationType = []RelationType{
+	RelationTypeSynonym,
+	RelationTypeAntonym,
+	RelationTypeHypernym,
+	RelationTypeDiminutive,
+	RelationTypeDerivedFrom,
+}
+
+func (e RelationType) IsValid() bool {
+	switch e {
+	case RelationTypeSynonym, RelationTypeAntonym, RelationTypeHypernym, RelationTypeDiminutive, RelationTypeDerivedFrom:
+		return true
+	}
+	return false
+}
+
+func (e RelationType) String() string {
+	return string(e)
+}
+
+func (e *RelationType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RelationType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RelationType", str)
+	}
+	return nil
+}
+
+func (e RelationType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type RevisionAction string
+
+const (
+	RevisionActionCreate  RevisionAction = "CREATE"
+	RevisionActionUpdate  RevisionAction = "UPDATE"
+	RevisionActionDelete  RevisionAction = "DELETE"
+	RevisionActionRestore RevisionAction = "RESTORE"
+)
+
+var AllRevisionAction = []RevisionAction{
+	RevisionActionCreate,
+	RevisionActionUpdate,
+	RevisionActionDelete,
+	RevisionActionRestore,
+}
+
+func (e RevisionAction) IsValid() bool {
+	switch e {
+	case RevisionActionCreate, RevisionActionUpdate, RevisionActionDelete, RevisionActionRestore:
+		return true
+	}
+	return false
+}
+
+func (e RevisionAction) String() string {
+	return string(e)
+}
+
+func (e *RevisionAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RevisionAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RevisionAction", str)
+	}
+	return nil
+}
+
+func (e RevisionAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type RevisionEntity string
+
+const (
+	RevisionEntityWord         RevisionEntity = "WORD"
+	RevisionEntityTranslation  RevisionEntity = "TRANSLATION"
+	RevisionEntityExample      RevisionEntity = "EXAMPLE"
+	RevisionEntityInflection   RevisionEntity = "INFLECTION"
+	RevisionEntityWordRelation RevisionEntity = "WORD_RELATION"
+)
+
+var AllRevisionEntity = []RevisionEntity{
+	RevisionEntityWord,
+	RevisionEntityTranslation,
+	RevisionEntityExample,
+	RevisionEntityInflection,
+	RevisionEntityWordRelation,
+}
+
+func (e RevisionEntity) IsValid() bool {
+	switch e {
+	case RevisionEntityWord, RevisionEntityTranslation, RevisionEntityExample, RevisionEntityInflection, RevisionEntityWordRelation:
+		return true
+	}
+	return false
+}
+
+func (e RevisionEntity) String() string {
+	return string(e)
+}
+
+func (e *RevisionEntity) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RevisionEntity(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RevisionEntity", str)
+	}
+	return nil
+}
+
+func (e RevisionEntity) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SearchMode string
+
+const (
+	SearchModeExact    SearchMode = "EXACT"
+	SearchModePrefix   SearchMode = "PREFIX"
+	SearchModeContains SearchMode = "CONTAINS"
+)
+
+var AllSearchMode = []SearchMode{
+	SearchModeExact,
+	SearchModePrefix,
+	SearchModeContains,
+}
+
+func (e SearchMode) IsValid() bool {
+	switch e {
+	case SearchModeExact, SearchModePrefix, SearchModeContains:
+		return true
+	}
+	return false
+}
+
+func (e SearchMode) String() string {
+	return string(e)
+}
+
+func (e *SearchMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchMode", str)
+	}
+	return nil
+}
+
+func (e SearchMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type WordOrderField string
+
+const (
+	WordOrderFieldID         WordOrderField = "ID"
+	WordOrderFieldTerm       WordOrderField = "TERM"
+	WordOrderFieldPolishWord WordOrderField = "POLISH_WORD"
+)
+
+var AllWordOrderField = []WordOrderField{
+	WordOrderFieldID,
+	WordOrderFieldTerm,
+	WordOrderFieldPolishWord,
+}
+
+func (e WordOrderField) IsValid() bool {
+	switch e {
+	case WordOrderFieldID, WordOrderFieldTerm, WordOrderFieldPolishWord:
+		return true
+	}
+	return false
+}
+
+func (e WordOrderField) String() string {
+	return string(e)
+}
+
+func (e *WordOrderField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WordOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WordOrderField", str)
+	}
+	return nil
+}
+
+func (e WordOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
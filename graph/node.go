@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"errors"
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+)
+
+// loadNode fetches the entity behind a global ID. Nested lists are left to the field resolvers.
+// Unknown types and missing rows are reported as NOT_FOUND.
+func loadNode(db *gorm.DB, field string, globalID string) (model.Node, error) {
+	typeName, id, err := splitGlobalID(field, globalID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch typeName {
+	case wordType:
+		var word models.Word
+		err = db.First(&word, id).Error
+		if err == nil {
+			return ToGraphQLWord(&word), nil
+		}
+	case translationType:
+		var translation models.Translation
+		err = withTerms(db).First(&translation, "translations.id = ?", id).Error
+		if err == nil {
+			return ToGraphQLTranslation(&translation), nil
+		}
+	case exampleType:
+		var example models.Example
+		err = withSentences(db).First(&example, "examples.id = ?", id).Error
+		if err == nil {
+			return ToGraphQLExample(&example), nil
+		}
+	case inflectionType:
+		var inflection models.Inflection
+		err = liveInflections(db).First(&inflection, id).Error
+		if err == nil {
+			return ToGraphQLInflection(&inflection), nil
+		}
+	case revisionType:
+		var revision models.Revision
+		err = db.First(&revision, id).Error
+		if err == nil {
+			return ToGraphQLRevision(&revision), nil
+		}
+	default:
+		err = gorm.ErrRecordNotFound
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.NewNotFound(field, "node not found: %s", globalID)
+	}
+	return nil, apperrors.NewInternal(err)
+}
@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// wordCursor is the decoded position of a word in the ordered words list
+type wordCursor struct {
+	ID   uint
+	Term string
+}
+
+// ordersByTerm tells whether the ordering sorts words by term, POLISH_WORD being its deprecated name
+func ordersByTerm(orderBy model.WordOrderField) bool {
+	return orderBy == model.WordOrderFieldTerm || orderBy == model.WordOrderFieldPolishWord
+}
+
+// encodeWordCursor builds an opaque cursor holding the sort key of the word.
+// The ID is always kept as a tie-breaker, so the ordering stays stable.
+func encodeWordCursor(word *models.Word, orderBy model.WordOrderField) string {
+	raw := fmt.Sprintf("%s:%d", orderBy, word.ID)
+	if ordersByTerm(orderBy) {
+		raw += ":" + word.Term
+	}
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeWordCursor reverses encodeWordCursor. Cursors created for a different ordering are rejected.
+// The field names the argument the cursor came from, for error reporting.
+func decodeWordCursor(cursor string, field string, orderBy model.WordOrderField) (wordCursor, error) {
+	var c wordCursor
+
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return c, apperrors.NewValidation(field, "invalid cursor: %s", cursor)
+	}
+
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) < 2 || parts[0] != orderBy.String() {
+		return c, apperrors.NewValidation(field, "invalid cursor for ordering %s: %s", orderBy, cursor)
+	}
+
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return c, apperrors.NewValidation(field, "invalid cursor: %s", cursor)
+	}
+	c.ID = uint(id)
+
+	if ordersByTerm(orderBy) {
+		if len(parts) != 3 {
+			return c, apperrors.NewValidation(field, "invalid cursor: %s", cursor)
+		}
+		c.Term = parts[2]
+	}
+
+	return c, nil
+}
+
+// afterWordCursor keeps only the words placed after (or before, when reversed) the cursor.
+// Row comparison lets Postgres walk the index instead of using OFFSET.
+func afterWordCursor(query *gorm.DB, c wordCursor, orderBy model.WordOrderField, reversed bool) *gorm.DB {
+	op := ">"
+	if reversed {
+		op = "<"
+	}
+
+	if ordersByTerm(orderBy) {
+		return query.Where("(term, id) "+op+" (?, ?)", c.Term, c.ID)
+	}
+	return query.Where("id "+op+" ?", c.ID)
+}
+
+// wordsOutsideCursor tells whether any word lies at the cursor or before it (after it, when reversed),
+// i.e. outside a page starting right after (before) the cursor. It tells exactly whether the page has a neighbour
+// on that side, even when the word of the cursor has been deleted since.
+func wordsOutsideCursor(query *gorm.DB, c wordCursor, orderBy model.WordOrderField, reversed bool) (bool, error) {
+	op := "<="
+	if reversed {
+		op = ">="
+	}
+
+	if ordersByTerm(orderBy) {
+		query = query.Where("(term, id) "+op+" (?, ?)", c.Term, c.ID)
+	} else {
+		query = query.Where("id "+op+" ?", c.ID)
+	}
+
+	var ids []uint
+	if err := query.Limit(1).Pluck("id", &ids).Error; err != nil {
+		return false, err
+	}
+	return len(ids) > 0, nil
+}
+
+// orderWords sorts words by the requested field, descending when paginating backwards
+func orderWords(query *gorm.DB, orderBy model.WordOrderField, reversed bool) *gorm.DB {
+	direction := "ASC"
+	if reversed {
+		direction = "DESC"
+	}
+
+	if ordersByTerm(orderBy) {
+		query = query.Order("term " + direction)
+	}
+	return query.Order("id " + direction)
+}
+
+// pageSize validates the first/last arguments and returns the number of rows to fetch
+func pageSize(first *int32, last *int32) (int, error) {
+	if first != nil && last != nil {
+		return 0, apperrors.NewValidation("last", "first and last cannot be used together")
+	}
+
+	size, field := defaultPageSize, "first"
+	if first != nil {
+		size = int(*first)
+	}
+	if last != nil {
+		size, field = int(*last), "last"
+	}
+
+	if size < 0 || size > maxPageSize {
+		return 0, apperrors.NewValidation(field, "page size must be between 0 and %d", maxPageSize)
+	}
+	return size, nil
+}
+
+// limitSize validates the limit argument of list queries which are not paginated
+func limitSize(limit *int32) (int, error) {
+	size := defaultPageSize
+	if limit != nil {
+		size = int(*limit)
+	}
+
+	if size < 0 || size > maxPageSize {
+		return 0, apperrors.NewValidation("limit", "limit must be between 0 and %d", maxPageSize)
+	}
+	return size, nil
+}
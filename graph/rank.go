@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"sort"
+	"translatorapi/apperrors"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rankOrder lists the translations of a word from its primary meaning, ties are left in insertion order
+const rankOrder = "translations.rank, translations.id"
+
+// nextRank returns the rank of a new translation of the word, which comes after the current ones.
+// The word row is locked first, so concurrent translations of the same word get distinct ranks.
+func nextRank(tx *gorm.DB, wordID uint) (int, error) {
+	var ids []uint
+	if err := tx.Model(&models.Word{}).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("id = ?", wordID).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	var rank int
+	err := tx.Model(&models.Translation{}).
+		Select("COALESCE(MAX(rank), 0) + 1").
+		Where("word_id = ?", wordID).
+		Scan(&rank).Error
+	return rank, err
+}
+
+// reorderTranslations ranks the translations of the word into targetLanguage in the order of targetTerms.
+// Translations not listed, including those into other languages, keep their order after the listed ones.
+// The translations are locked first, so concurrent reorders of the same word run one after another.
+func reorderTranslations(tx *gorm.DB, word models.Word, targetLanguage string, targetTerms []string, field string) ([]models.Translation, error) {
+	var translations []models.Translation
+	if err := withTerms(tx).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Table: clause.Table{Name: clause.CurrentTable}}).
+		Where("translations.word_id = ?", word.ID).
+		Order("translations.id").
+		Find(&translations).Error; err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+	sort.SliceStable(translations, func(i, j int) bool {
+		return translations[i].Rank < translations[j].Rank
+	})
+
+	byTerm := make(map[string]models.Translation, len(translations))
+	for _, translation := range translations {
+		if translation.TargetWord.LanguageCode == targetLanguage {
+			byTerm[translation.TargetWord.Term] = translation
+		}
+	}
+
+	listed := make(map[uint]bool, len(targetTerms))
+	ordered := make([]models.Translation, 0, len(translations))
+	for _, term := range targetTerms {
+		translation, ok := byTerm[term]
+		if !ok {
+			return nil, apperrors.NewNotFound(field, "translation not found: %s", term)
+		}
+		if listed[translation.ID] {
+			return nil, apperrors.NewValidation(field, "translation listed more than once: %s", term)
+		}
+		listed[translation.ID] = true
+		ordered = append(ordered, translation)
+	}
+	for _, translation := range translations {
+		if !listed[translation.ID] {
+			ordered = append(ordered, translation)
+		}
+	}
+
+	for i := range ordered {
+		if ordered[i].Rank == i+1 {
+			continue
+		}
+		ordered[i].Rank = i + 1
+		if err := tx.Model(&models.Translation{}).Where("id = ?", ordered[i].ID).Update("rank", ordered[i].Rank).Error; err != nil {
+			return nil, apperrors.NewInternal(err)
+		}
+	}
+	return ordered, nil
+}
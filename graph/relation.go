@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+)
+
+// isSymmetric tells whether the relation holds both ways, like synonyms do
+func isSymmetric(relationType model.RelationType) bool {
+	return relationType == model.RelationTypeSynonym || relationType == model.RelationTypeAntonym
+}
+
+// relationEnds returns the word IDs a relation between the two words is stored under.
+// Symmetric relations are stored from the word with the lower ID, so each pair is stored once.
+func relationEnds(wordID uint, relatedWordID uint, relationType model.RelationType) (uint, uint) {
+	if isSymmetric(relationType) && wordID > relatedWordID {
+		return relatedWordID, wordID
+	}
+	return wordID, relatedWordID
+}
+
+// withRelatedWords loads both words of word relations.
+// Inner joins skip the relations of deleted words, which come back when the word is restored.
+func withRelatedWords(tx *gorm.DB) *gorm.DB {
+	return tx.InnerJoins("Word").InnerJoins("RelatedWord")
+}
+
+// findRelationWords finds the two words of a relation, each either by its global ID or by its Polish term.
+// A word cannot be related to itself nor to a word of another language, which would be a translation.
+func findRelationWords(tx *gorm.DB, wordID *string, polishWord *string, relatedWordID *string, relatedPolishWord *string) (models.Word, models.Word, error) {
+	word, err := findWordByKey(tx, "wordId", wordID, polishWord)
+	if err != nil {
+		return word, models.Word{}, err
+	}
+
+	related, err := findWordByTermKey(tx, "relatedWordId", relatedWordID, "relatedPolishWord", relatedPolishWord, languagePolish)
+	if err != nil {
+		return word, related, err
+	}
+
+	if word.ID == related.ID {
+		return word, related, apperrors.NewValidation("relatedWordId", "a word cannot be related to itself")
+	}
+	if word.LanguageCode != related.LanguageCode {
+		return word, related, apperrors.NewValidation("relatedWordId", "related words must be in the same language, got %s and %s", word.LanguageCode, related.LanguageCode)
+	}
+	return word, related, nil
+}
+
+// linkWords stores the relation from word to related.
+// An asymmetric relation already stored the other way round would contradict it, so it is rejected.
+func linkWords(tx *gorm.DB, word models.Word, related models.Word, relationType model.RelationType) (models.WordRelation, error) {
+	from, to := relationEnds(word.ID, related.ID, relationType)
+	relation := models.WordRelation{WordID: from, RelatedWordID: to, Type: relationType.String()}
+
+	if !isSymmetric(relationType) {
+		var count int64
+		if err := tx.Model(&models.WordRelation{}).
+			Where("word_id = ? AND related_word_id = ? AND type = ?", to, from, relation.Type).
+			Count(&count).Error; err != nil {
+			return relation, apperrors.NewInternal(err)
+		}
+		if count > 0 {
+			return relation, apperrors.NewValidation("relatedWordId", "the reverse %s relation already exists", relation.Type)
+		}
+	}
+
+	result := tx.Where(&relation).FirstOrCreate(&relation)
+
+	// If there was an error
+	if result.Error != nil {
+		return relation, apperrors.FromDB(result.Error, "relatedWordId", "relation already exists: %s", relation.Type)
+	}
+
+	// No error, check if the relation was created or already existed
+	if result.RowsAffected == 0 {
+		return relation, apperrors.NewAlreadyExists("relatedWordId", "relation already exists: %s", relation.Type)
+	}
+
+	if relation.WordID == word.ID {
+		relation.Word, relation.RelatedWord = word, related
+	} else {
+		relation.Word, relation.RelatedWord = related, word
+	}
+	return relation, nil
+}
+
+// findRelation finds the relation from word to related, failing with NOT_FOUND when it is not stored
+func findRelation(tx *gorm.DB, word models.Word, related models.Word, relationType model.RelationType) (models.WordRelation, error) {
+	from, to := relationEnds(word.ID, related.ID, relationType)
+
+	var relations []models.WordRelation
+	if err := tx.Where("word_id = ? AND related_word_id = ? AND type = ?", from, to, relationType.String()).Find(&relations).Error; err != nil {
+		return models.WordRelation{}, apperrors.NewInternal(err)
+	}
+	if len(relations) == 0 {
+		return models.WordRelation{}, apperrors.NewNotFound("relatedWordId", "relation not found: %s", relationType)
+	}
+	return relations[0], nil
+}
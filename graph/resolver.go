@@ -4,60 +4,136 @@ package graph
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"strings"
+	"time"
+	"translatorapi/apperrors"
 	generated1 "translatorapi/graph/generated"
 	"translatorapi/graph/model"
+	"translatorapi/lemmatizer"
 	"translatorapi/models"
 
 	// "github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Resolver struct {
 	DB *gorm.DB
+	// Lemmatizer resolves inflected forms missing from the inflection tables, the rule-based one is used when nil
+	Lemmatizer lemmatizer.Lemmatizer
+	// ExampleValidation is the server-wide check of new example sentences, OFF when empty.
+	// createExample and updateExample may override it per call
+	ExampleValidation model.ExampleValidation
 }
 
-// CreateWord creates a new Polish word.
-func (r *mutationResolver) CreateWord(ctx context.Context, polishWord string, englishWord *string, sentence *string) (*model.Word, error) {
+// CreateTerm creates a new word in any language, optionally with its grammatical metadata and entry kind.
+func (r *mutationResolver) CreateTerm(ctx context.Context, term string, languageCode string, partOfSpeech *model.PartOfSpeech, gender *model.Gender, aspect *model.Aspect, note *string, kind *model.EntryKind) (*model.Word, error) {
+	if err := validateLanguage("languageCode", languageCode); err != nil {
+		return nil, err
+	}
+
 	var word models.Word
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		word, err = createTerm(tx, "term", term, languageCode, partOfSpeech, gender, aspect, note, kind)
+		if err != nil {
+			return err
+		}
+
+		log := newRevisionLog(tx, "createTerm")
+		log.created(model.RevisionEntityWord, word.ID)
+		return log.record()
+	})
 
-	err := r.DB.Transaction(func(tx *gorm.DB) error {
-		word = models.Word{PolishWord: polishWord}
+	if err != nil {
+		return nil, err // triggers rollback
+	}
 
-		result := tx.Where(&word).FirstOrCreate(&word)
+	return ToGraphQLWord(&word), nil
+}
 
-		// If there was an error
-		if result.Error != nil {
-			return fmt.Errorf("failed to create word: %v", result.Error)
+// AddTranslation links a word to a term of another language, optionally with usage labels.
+// The source word is found either by sourceId or by sourceTerm and sourceLanguage.
+func (r *mutationResolver) AddTranslation(ctx context.Context, sourceID *string, sourceTerm *string, sourceLanguage *string, targetTerm string, targetLanguage string, sentence *string, register *model.Register, domain *string, region *model.Region) (*model.Translation, error) {
+	if err := validateLanguage("targetLanguage", targetLanguage); err != nil {
+		return nil, err
+	}
+
+	var language string
+	if sourceLanguage != nil {
+		if sourceTerm == nil {
+			return nil, apperrors.NewValidation("sourceLanguage", "sourceLanguage can only be used with sourceTerm")
+		}
+		if err := validateLanguage("sourceLanguage", *sourceLanguage); err != nil {
+			return nil, err
 		}
+		language = *sourceLanguage
+	} else if sourceTerm != nil {
+		return nil, apperrors.NewValidation("sourceLanguage", "sourceLanguage is required with sourceTerm")
+	}
 
-		// No error, check if the word was created or already existed
-		if result.RowsAffected == 0 {
-			// No rows were affected, meaning the word already existed
-			return fmt.Errorf("word already exists: %s", polishWord)
+	var translation models.Translation
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		word, err := findWordByTermKey(tx, "sourceId", sourceID, "sourceTerm", sourceTerm, language)
+		if err != nil {
+			return err
 		}
-		// Optionally add translation and example
-		if englishWord != nil {
-			translation := models.Translation{
-				EnglishWord: *englishWord,
-				WordID:      word.ID,
+
+		log := newRevisionLog(tx, "addTranslation")
+		translation, err = addTranslation(tx, log, word, targetTerm, targetLanguage, sentence, "targetTerm", "targetLanguage", r.exampleValidator(nil))
+		if err != nil {
+			return err
+		}
+		if err := log.createdTranslation(translation.ID); err != nil {
+			return err
+		}
+
+		if register != nil || domain != nil || region != nil {
+			if err := setLabels(&translation, register, domain, region); err != nil {
+				return err
 			}
-			if err := tx.Create(&translation).Error; err != nil {
+			if err := saveLabels(tx, &translation); err != nil {
 				return err
 			}
+		}
+		return log.record()
+	})
 
-			if sentence != nil {
-				example := models.Example{
-					Sentence:      *sentence,
-					TranslationID: translation.ID,
-				}
-				if err := tx.Create(&example).Error; err != nil {
-					return err
-				}
+	if err != nil {
+		return nil, err // triggers rollback
+	}
+
+	return ToGraphQLTranslation(&translation), nil
+}
+
+// CreateWord creates a new Polish word, optionally with its grammatical metadata, entry kind and an English translation.
+func (r *mutationResolver) CreateWord(ctx context.Context, polishWord string, englishWord *string, sentence *string, partOfSpeech *model.PartOfSpeech, gender *model.Gender, aspect *model.Aspect, note *string, kind *model.EntryKind) (*model.Word, error) {
+	var word models.Word
+
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		word, err = createTerm(tx, "polishWord", polishWord, languagePolish, partOfSpeech, gender, aspect, note, kind)
+		if err != nil {
+			return err
+		}
+
+		log := newRevisionLog(tx, "createWord")
+		log.created(model.RevisionEntityWord, word.ID)
+
+		// Optionally add translation and example
+		if englishWord != nil {
+			translation, err := addTranslation(tx, log, word, *englishWord, languageEnglish, sentence, "englishWord", "englishWord", r.exampleValidator(nil))
+			if err != nil {
+				return err
+			}
+			if err := log.createdTranslation(translation.ID); err != nil {
+				return err
 			}
 		}
 
-		return nil // triggers commit
+		return log.record() // triggers commit
 	})
 
 	if err != nil {
@@ -67,18 +143,15 @@ func (r *mutationResolver) CreateWord(ctx context.Context, polishWord string, en
 	return ToGraphQLWord(&word), nil
 }
 
-// CreateTranslation creates a new translation for a word.
-func (r *mutationResolver) CreateTranslation(ctx context.Context, polishWord string, englishWord string, sentence *string) (*model.Translation, error) {
+// CreateTranslation creates a new English translation for a Polish word.
+// The word is found either by wordId or by polishWord.
+func (r *mutationResolver) CreateTranslation(ctx context.Context, polishWord *string, englishWord string, sentence *string, wordID *string) (*model.Translation, error) {
 	var translation models.Translation
-	err := r.DB.Transaction(func(tx *gorm.DB) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
-		// Find the word by its PolishWord
-		var word models.Word
-		if err := tx.Where("polish_word = ?", polishWord).First(&word).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return fmt.Errorf("polish word not found: %s", polishWord)
-			}
-			return fmt.Errorf("an error occurred: %v", err)
+		word, err := findWordByKey(tx, "wordId", wordID, polishWord)
+		if err != nil {
+			return err
 		}
 
 		// // Check if the translation already exists
@@ -88,40 +161,18 @@ func (r *mutationResolver) CreateTranslation(ctx context.Context, polishWord str
 		// 	if err == gorm.ErrRecordNotFound {
 		// 		return  fmt.Errorf("translation '%s' already exists for this word", englishWord)
 		// 	}
-		// 	return  fmt.Errorf("an error occurred: %v", err)
+		// 	return  apperrors.NewInternal(err)
 		// }
 
-		// Create the translation for the found word
-		translation = models.Translation{
-			WordID:      word.ID,
-			EnglishWord: englishWord,
-		}
-
-		result := tx.Where(&translation).FirstOrCreate(&translation)
-
-		// If there was an error
-		if result.Error != nil {
-			return fmt.Errorf("failed to create word: %v", result.Error)
-		}
-
-		// No error, check if the word was created or already existed
-		if result.RowsAffected == 0 {
-			// No rows were affected, meaning the word already existed
-			return fmt.Errorf("translation already exists: %s", englishWord)
+		log := newRevisionLog(tx, "createTranslation")
+		translation, err = addTranslation(tx, log, word, englishWord, languageEnglish, sentence, "englishWord", "englishWord", r.exampleValidator(nil))
+		if err != nil {
+			return err
 		}
-
-		if sentence != nil {
-			example := models.Example{
-				TranslationID: translation.ID,
-				Sentence:      *sentence,
-			}
-
-			if err := tx.Create(&example).Error; err != nil {
-				return err
-			}
+		if err := log.createdTranslation(translation.ID); err != nil {
+			return err
 		}
-
-		return nil // triggers commit
+		return log.record()
 	})
 
 	if err != nil {
@@ -131,13 +182,16 @@ func (r *mutationResolver) CreateTranslation(ctx context.Context, polishWord str
 	return ToGraphQLTranslation(&translation), nil
 }
 
-// CreateExample creates a new example sentence for a translation.
-func (r *mutationResolver) CreateExample(ctx context.Context, polishWord string, englishWord string, sentence string) (*model.Example, error) {
+// CreateExample creates a new example sentence for a translation, optionally with its parallel sentence.
+// The translation is found either by translationId or by polishWord and englishWord.
+func (r *mutationResolver) CreateExample(ctx context.Context, polishWord *string, englishWord *string, sentence string, translationID *string, languageCode *string, parallelSentence *string, validation *model.ExampleValidation) (*model.Example, error) {
+	validate := r.exampleValidator(validation)
+
 	var example models.Example
-	err := r.DB.Transaction(func(tx *gorm.DB) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
 		if tx.Error != nil {
-			return fmt.Errorf("could not begin transaction: %w", tx.Error)
+			return apperrors.NewInternal(tx.Error)
 		}
 
 		// Ensure rollback in case of panic
@@ -147,20 +201,14 @@ func (r *mutationResolver) CreateExample(ctx context.Context, polishWord string,
 			}
 		}()
 
-		var word models.Word
-		if err := tx.Where("polish_word = ?", polishWord).First(&word).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return fmt.Errorf("polish word not found: %s", polishWord)
-			}
-			return fmt.Errorf("an error occurred: %v", err)
+		translation, err := findTranslationByKey(tx, "translationId", translationID, polishWord, englishWord)
+		if err != nil {
+			return err
 		}
 
-		var translation models.Translation
-		if err := tx.Where("word_id = ? AND english_word = ?", word.ID, englishWord).First(&translation).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return fmt.Errorf("translation maching polish word not found: %s", englishWord)
-			}
-			return fmt.Errorf("an error occurred: %v", err)
+		language, parallelLanguage, err := exampleLanguages(translation, languageCode)
+		if err != nil {
+			return err
 		}
 
 		// // Check if the example already exists
@@ -169,29 +217,51 @@ func (r *mutationResolver) CreateExample(ctx context.Context, polishWord string,
 		// 	if err == gorm.ErrRecordNotFound {
 		// 		return fmt.Errorf("example '%s' already exists for this translation", sentence)
 		// 	}
-		// 	return fmt.Errorf("an error occurred: %v", err)
+		// 	return apperrors.NewInternal(err)
 		// }
 
+		// Sentences are shared, so an existing one is reused instead of duplicated
+		exampleSentence, err := findOrCreateSentence(tx, sentence)
+		if err != nil {
+			return apperrors.NewInternal(err)
+		}
+
 		// Create the example for the found translation
 		example = models.Example{
 			TranslationID: translation.ID,
-			Sentence:      sentence,
+			SentenceID:    exampleSentence.ID,
+			Sentence:      exampleSentence,
+			LanguageCode:  language,
+		}
+		if err := validate(tx, translation, &example); err != nil {
+			return err
+		}
+		if parallelSentence != nil {
+			if err := setParallelSentence(tx, &example, *parallelSentence, parallelLanguage); err != nil {
+				return err
+			}
 		}
 
-		result := tx.Where(&example).FirstOrCreate(&example)
+		// The sentence alone identifies the example of a translation, its language and parallel sentence do not.
+		// The parallel sentence is already stored, so associations are not saved again.
+		result := tx.Omit(clause.Associations).
+			Where(&models.Example{TranslationID: translation.ID, SentenceID: exampleSentence.ID}).
+			FirstOrCreate(&example)
 
 		// If there was an error
 		if result.Error != nil {
-			return fmt.Errorf("failed to create example: %v", result.Error)
+			return apperrors.FromDB(result.Error, "sentence", "example already exists: %s", sentence)
 		}
 
 		// No error, check if the word was created or already existed
 		if result.RowsAffected == 0 {
 			// No rows were affected, meaning the word already existed
-			return fmt.Errorf("example already exists: %s", sentence)
+			return apperrors.NewAlreadyExists("sentence", "example already exists: %s", sentence)
 		}
 
-		return nil
+		log := newRevisionLog(tx, "createExample")
+		log.created(model.RevisionEntityExample, example.ID)
+		return log.record()
 	})
 
 	if err != nil {
@@ -201,64 +271,136 @@ func (r *mutationResolver) CreateExample(ctx context.Context, polishWord string,
 	return ToGraphQLExample(&example), nil
 }
 
-func (r *mutationResolver) ReplaceTranslation(ctx context.Context, polishWord string, englishWord string, newTranslation string) (*model.Translation, error) {
+// ReplaceTranslation replaces a translation of the word with a new term of the same target language.
+// Unless preserveExamples is false, the examples of the old translation are carried over to the new one.
+// The old translation is found either by translationId or by polishWord and englishWord.
+func (r *mutationResolver) ReplaceTranslation(ctx context.Context, polishWord *string, englishWord *string, newTranslation string, preserveExamples *bool, translationID *string, expectedVersion *int32) (*model.Translation, error) {
+	preserve := preserveExamples == nil || *preserveExamples
+
 	var translation models.Translation
-	err := r.DB.Transaction(func(tx *gorm.DB) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
-		// Find the word by its PolishWord
 		var word models.Word
-		if err := tx.Where("polish_word = ?", polishWord).First(&word).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return fmt.Errorf("word not found: %v", err)
+		var targetLanguage string
+		var oldTranslationIDs *gorm.DB
+		if translationID != nil {
+			// An ID points at one translation, so it has to exist
+			oldTranslation, err := findTranslationByKey(tx, "translationId", translationID, polishWord, englishWord)
+			if err != nil {
+				return err
 			}
-			return fmt.Errorf("an error occurred: %v", err)
-		}
-
-		if err := tx.Where("word_id = ? AND english_word = ?", word.ID, englishWord).Delete(models.Translation{}).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				// Create the translation for the found word
-				translation := models.Translation{
-					WordID:      word.ID,
-					EnglishWord: newTranslation,
+			word = oldTranslation.Word
+			targetLanguage = oldTranslation.TargetWord.LanguageCode
+			oldTranslationIDs = tx.Model(&models.Translation{}).Select("id").Where("id = ?", oldTranslation.ID)
+		} else {
+			if polishWord == nil || englishWord == nil {
+				field := "englishWord"
+				if polishWord == nil {
+					field = "polishWord"
 				}
+				return apperrors.NewValidation(field, "either translationId or polishWord and englishWord are required")
+			}
 
-				result := tx.Where(&translation).FirstOrCreate(&translation)
-
-				// If there was an error
-				if result.Error != nil {
-					return fmt.Errorf("failed to create translation: %v", result.Error)
-				}
+			// Find the word by its Polish term
+			var err error
+			word, err = findWordByKey(tx, "polishWord", nil, polishWord)
+			if err != nil {
+				return err
+			}
+			targetLanguage = languageEnglish
+			targetWordIDs := tx.Model(&models.Word{}).Select("id").Where("language_code = ? AND term = ?", languageEnglish, *englishWord)
+			oldTranslationIDs = tx.Model(&models.Translation{}).Select("id").Where("word_id = ? AND target_word_id IN (?)", word.ID, targetWordIDs)
+		}
 
-				// No error, check if the word was created or already existed
-				if result.RowsAffected == 0 {
-					// No rows were affected, meaning the word already existed
-					return fmt.Errorf("translation already exists: %s", newTranslation)
-				}
-				return nil
+		// The usage labels and the rank belong to the meaning, which the new term keeps
+		var oldTranslations []models.Translation
+		if err := tx.Where("id IN (?)", oldTranslationIDs).Order("id").Limit(1).Find(&oldTranslations).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+		if expectedVersion != nil {
+			// Without an old translation there is nothing left at the expected version
+			var oldTranslationID uint
+			if len(oldTranslations) > 0 {
+				oldTranslationID = oldTranslations[0].ID
+			}
+			if err := checkVersion(tx, model.RevisionEntityTranslation, oldTranslationID, expectedVersion); err != nil {
+				return err
+			}
+		}
 
+		// Remember the examples of the old translation before they are deleted along with it
+		var oldExamples []models.Example
+		if preserve {
+			if err := withSentences(tx).Where("examples.translation_id IN (?)", oldTranslationIDs).Order("examples.id").Find(&oldExamples).Error; err != nil {
+				return apperrors.NewInternal(err)
 			}
-			return fmt.Errorf("operation unsucesfull: %w", err)
 		}
 
-		// Create the translation for the found word
-		translation = models.Translation{
-			WordID:      word.ID,
-			EnglishWord: newTranslation,
+		var trackedIDs []uint
+		if err := tx.Model(&models.Translation{}).Where("id IN (?)", oldTranslationIDs).Order("id").Pluck("id", &trackedIDs).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+		log := newRevisionLog(tx, "replaceTranslation")
+		if err := log.trackTranslations(trackedIDs...); err != nil {
+			return err
 		}
 
-		result := tx.Where(&translation).FirstOrCreate(&translation)
+		// Move the old translation to the trash, the target word itself stays shared with other words
+		if err := deleteTranslations(tx, oldTranslationIDs, tx.NowFunc()); err != nil {
+			return err
+		}
 
-		// If there was an error
-		if result.Error != nil {
-			return fmt.Errorf("failed to create translation: %v", result.Error)
+		var err error
+		translation, err = addTranslation(tx, log, word, newTranslation, targetLanguage, nil, "newTranslation", "newTranslation", r.exampleValidator(nil))
+		if err != nil {
+			return err
 		}
 
-		// No error, check if the word was created or already existed
-		if result.RowsAffected == 0 {
-			// No rows were affected, meaning the word already existed
-			return fmt.Errorf("translation already exists: %s", newTranslation)
+		if len(oldTranslations) > 0 {
+			old := oldTranslations[0]
+			translation.Register, translation.Domain, translation.Region = old.Register, old.Domain, old.Region
+			if err := saveLabels(tx, &translation); err != nil {
+				return err
+			}
+
+			// The new term takes the place of the old one among the meanings of the word
+			if err := tx.Model(&translation).Omit(clause.Associations).Update("rank", old.Rank).Error; err != nil {
+				return apperrors.NewInternal(err)
+			}
 		}
-		return nil
+
+		// Sentences are shared rows, so carrying examples over only needs new links.
+		// The target language is kept, so the languages of the sentences still fit,
+		// but a sentence matching the old term may not contain the new one, so each example is validated again.
+		validate := r.exampleValidator(nil)
+		for _, oldExample := range oldExamples {
+			example := models.Example{
+				TranslationID:        translation.ID,
+				SentenceID:           oldExample.SentenceID,
+				Sentence:             oldExample.Sentence,
+				LanguageCode:         oldExample.LanguageCode,
+				ParallelSentenceID:   oldExample.ParallelSentenceID,
+				ParallelLanguageCode: oldExample.ParallelLanguageCode,
+			}
+			if err := validate(tx, translation, &example); err != nil {
+				var appErr *apperrors.Error
+				if errors.As(err, &appErr) && appErr.Code == apperrors.Validation {
+					return apperrors.NewValidation("newTranslation", "the preserved example %q does not contain %q or any of its forms", example.Sentence.Text, newTranslation)
+				}
+				return err
+			}
+			translation.Examples = append(translation.Examples, example)
+		}
+		if len(translation.Examples) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&translation.Examples).Error; err != nil {
+				return apperrors.NewInternal(err)
+			}
+		}
+
+		if err := log.createdTranslation(translation.ID); err != nil {
+			return err
+		}
+		return log.record()
 	})
 
 	if err != nil {
@@ -269,174 +411,1036 @@ func (r *mutationResolver) ReplaceTranslation(ctx context.Context, polishWord st
 
 }
 
-// DeleteWord is the resolver for the deleteWord field.
-func (r *mutationResolver) DeleteWord(ctx context.Context, polishWord string) (bool, error) {
-	err := r.DB.Transaction(func(tx *gorm.DB) error {
+// ReorderTranslations ranks the English translations of a Polish word, the first one being its primary meaning.
+func (r *mutationResolver) ReorderTranslations(ctx context.Context, polishWord string, englishWords []string, expectedVersion *int32) ([]*model.Translation, error) {
+	if len(englishWords) == 0 {
+		return nil, apperrors.NewValidation("englishWords", "at least one English word is required")
+	}
 
-		var word models.Word
-		if err := tx.Where("polish_word = ?", polishWord).First(&word).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return fmt.Errorf("word not found: %s", polishWord)
-			}
-			return fmt.Errorf("an error occurred: %v", err)
+	var translations []models.Translation
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		word, err := findWordByKey(tx, "polishWord", nil, &polishWord)
+		if err != nil {
+			return err
 		}
 
-		if err := tx.Where("polish_word = ?", polishWord).Delete(&models.Word{}).Error; err != nil {
+		if err := checkVersion(tx, model.RevisionEntityWord, word.ID, expectedVersion); err != nil {
+			return err
+		}
+		var translationIDs []uint
+		if err := tx.Model(&models.Translation{}).Where("word_id = ?", word.ID).Order("id").Pluck("id", &translationIDs).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+		log := newRevisionLog(tx, "reorderTranslations")
+		if err := log.track(model.RevisionEntityTranslation, translationIDs...); err != nil {
 			return err
 		}
 
-		return nil
+		translations, err = reorderTranslations(tx, word, languageEnglish, englishWords, "englishWords")
+		if err != nil {
+			return err
+		}
+		if err := log.record(); err != nil {
+			return err
+		}
 
+		// The ranks order the meanings of the word, so a reorder changing them bumps its version as well
+		if len(log.revisions) > 0 {
+			if err := log.bump(model.RevisionEntityWord, word.ID); err != nil {
+				return err
+			}
+		}
+		for i := range translations {
+			translations[i].Version = log.version(model.RevisionEntityTranslation, translations[i].ID, translations[i].Version)
+		}
+		return nil
 	})
 
 	if err != nil {
-		return false, err // triggers rollback
+		return nil, err // triggers rollback
 	}
 
-	return true, nil
+	gqlTranslations := make([]*model.Translation, 0, len(translations))
+	for i := range translations {
+		gqlTranslations = append(gqlTranslations, ToGraphQLTranslation(&translations[i]))
+	}
+	return gqlTranslations, nil
 }
 
-// DeleteTranslation is the resolver for the deleteTranslation field.
-func (r *mutationResolver) DeleteTranslation(ctx context.Context, polishWord string, englishWord string) (bool, error) {
-	err := r.DB.Transaction(func(tx *gorm.DB) error {
+// UpdateWord changes the spelling or the grammatical metadata of a word, keeping its translations and examples.
+// Omitted arguments are left unchanged; polishWord is the deprecated name of term.
+func (r *mutationResolver) UpdateWord(ctx context.Context, id string, term *string, polishWord *string, partOfSpeech *model.PartOfSpeech, gender *model.Gender, aspect *model.Aspect, note *string, kind *model.EntryKind, expectedVersion *int32) (*model.Word, error) {
+	wordID, err := fromGlobalID("id", id, wordType)
+	if err != nil {
+		return nil, err
+	}
 
-		var word models.Word
-		if err := tx.Where("polish_word = ?", polishWord).First(&word).Error; err != nil {
+	termField := "term"
+	if polishWord != nil {
+		if term != nil {
+			return nil, apperrors.NewValidation("term", "provide either term or polishWord, not both")
+		}
+		term, termField = polishWord, "polishWord"
+	}
+
+	var word models.Word
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.First(&word, wordID).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
-				return fmt.Errorf("word not found: %s", polishWord)
+				return apperrors.NewNotFound("id", "word not found: %s", id)
 			}
-			return fmt.Errorf("an error occurred: %v", err)
+			return apperrors.NewInternal(err)
 		}
 
-		var translation models.Translation
-		if err := tx.Where("word_id = ? AND english_word = ?", word.ID, englishWord).First(&translation).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return fmt.Errorf("translation not found: %s", englishWord)
+		if err := checkVersion(tx, model.RevisionEntityWord, word.ID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "updateWord")
+		if err := log.track(model.RevisionEntityWord, word.ID); err != nil {
+			return err
+		}
+
+		if term != nil {
+			var count int64
+			if err := tx.Model(&models.Word{}).Where("language_code = ? AND term = ? AND id <> ?", word.LanguageCode, *term, word.ID).Count(&count).Error; err != nil {
+				return apperrors.NewInternal(err)
+			}
+			if count > 0 {
+				return apperrors.NewAlreadyExists(termField, "word already exists: %s", *term)
 			}
-			return fmt.Errorf("an error occurred: %v", err)
+			word.Term = *term
 		}
 
-		if err := tx.Where("word_id = ? AND english_word = ?", word.ID, englishWord).Delete(models.Translation{}).Error; err != nil {
+		if err := setGrammar(&word, partOfSpeech, gender, aspect, note); err != nil {
+			return err
+		}
+		if err := setKind(tx, &word, kind); err != nil {
 			return err
 		}
 
-		return nil
+		// Save runs the BeforeSave hook, so normalized_word follows the new spelling.
+		// The version is left to record, a concurrent change may have bumped it since the word was read
+		if err := tx.Omit("version").Save(&word).Error; err != nil {
+			return apperrors.FromDB(err, termField, "word already exists: %s", word.Term)
+		}
 
+		if err := log.record(); err != nil {
+			return err
+		}
+		word.Version = log.version(model.RevisionEntityWord, word.ID, word.Version)
+		return nil
 	})
 
 	if err != nil {
-		return false, err // triggers rollback
+		return nil, err // triggers rollback
 	}
 
-	return true, nil
+	return ToGraphQLWord(&word), nil
 }
 
-// DeleteExample is the resolver for the deleteExample field.
-func (r *mutationResolver) DeleteExample(ctx context.Context, polishWord string, englishWord string, exampleSentence string) (bool, error) {
-	err := r.DB.Transaction(func(tx *gorm.DB) error {
-
-		var word models.Word
-		if err := tx.Where("polish_word = ?", polishWord).First(&word).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return fmt.Errorf("word not found: %s", polishWord)
-			}
-			return fmt.Errorf("an error occurred: %v", err)
+// SetComponents replaces the words a multi-word entry is made of.
+// The entry is found either by wordId or by polishWord, its components are headwords of its language.
+func (r *mutationResolver) SetComponents(ctx context.Context, wordID *string, polishWord *string, components []string, expectedVersion *int32) (*model.Word, error) {
+	var word models.Word
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		word, err = findWordByKey(tx, "wordId", wordID, polishWord)
+		if err != nil {
+			return err
 		}
 
-		var translation models.Translation
-		if err := tx.Where("word_id = ? AND english_word = ?", word.ID, englishWord).First(&translation).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return fmt.Errorf("translation not found: %s", englishWord)
-			}
-			return fmt.Errorf("an error occurred: %v", err)
+		if err := checkVersion(tx, model.RevisionEntityWord, word.ID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "setComponents")
+		if err := log.track(model.RevisionEntityWord, word.ID); err != nil {
+			return err
 		}
 
-		var example models.Example
-		if err := tx.Where("translation_id = ? AND sentence = ?", translation.ID, exampleSentence).First(&example).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				return fmt.Errorf("example not found: %s", exampleSentence)
-			}
-			return fmt.Errorf("an error occurred: %v", err)
+		if _, err := setComponents(tx, word, components); err != nil {
+			return err
 		}
 
-		if err := tx.Where("translation_id = ? AND sentence = ?", translation.ID, exampleSentence).Delete(models.Example{}).Error; err != nil {
+		// The components are part of the entry, so changing them bumps its version
+		if err := log.record(); err != nil {
 			return err
 		}
+		word.Version = log.version(model.RevisionEntityWord, word.ID, word.Version)
 		return nil
 	})
 
 	if err != nil {
-		return false, err // triggers rollback
+		return nil, err // triggers rollback
 	}
 
-	return true, nil
+	return ToGraphQLWord(&word), nil
 }
 
-// Words is the resolver for the words field.
-func (r *queryResolver) Words(ctx context.Context) ([]*model.Word, error) {
-
-	var words []*models.Word
-	if err := r.DB.Preload("Translations.Examples").Find(&words).Error; err != nil {
+// UpdateTranslation points a translation at another term of the same target language, keeping its examples,
+// and changes its usage labels. The old target word is left untouched, since other words may still be translated by it.
+// englishWord is the deprecated name of targetTerm.
+func (r *mutationResolver) UpdateTranslation(ctx context.Context, id string, targetTerm *string, englishWord *string, register *model.Register, domain *string, region *model.Region, clearLabels *bool, expectedVersion *int32) (*model.Translation, error) {
+	translationID, err := fromGlobalID("id", id, translationType)
+	if err != nil {
 		return nil, err
 	}
 
-	var gqlWords []*model.Word
-	for _, word := range words {
-		gqlWords = append(gqlWords, ToGraphQLWord(word))
+	termField := "targetTerm"
+	if englishWord != nil {
+		if targetTerm != nil {
+			return nil, apperrors.NewValidation("targetTerm", "provide either targetTerm or englishWord, not both")
+		}
+		targetTerm, termField = englishWord, "englishWord"
+	}
+	clearAll := clearLabels != nil && *clearLabels
+	changesLabels := clearAll || register != nil || domain != nil || region != nil
+	if targetTerm == nil && !changesLabels {
+		return nil, apperrors.NewValidation("targetTerm", "either targetTerm or a label is required")
 	}
 
-	return gqlWords, nil
-}
+	var translation models.Translation
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
-// Translations retrieves translations by PolishWord.
-func (r *queryResolver) Translations(ctx context.Context, polishWord string) ([]*model.Translation, error) {
+		if err := withTerms(tx).First(&translation, "translations.id = ?", translationID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return apperrors.NewNotFound("id", "translation not found: %s", id)
+			}
+			return apperrors.NewInternal(err)
+		}
 
-	var word models.Word
-	if err := r.DB.Where("polish_word = ?", polishWord).First(&word).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("word not found: %s", polishWord)
+		if err := checkVersion(tx, model.RevisionEntityTranslation, translation.ID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "updateTranslation")
+		if err := log.track(model.RevisionEntityTranslation, translation.ID); err != nil {
+			return err
+		}
+		if changesLabels {
+			if clearAll {
+				translation.Register, translation.Domain, translation.Region = nil, nil, nil
+			}
+			if err := setLabels(&translation, register, domain, region); err != nil {
+				return err
+			}
+			if err := saveLabels(tx, &translation); err != nil {
+				return err
+			}
 		}
-		return nil, fmt.Errorf("an error occurred: %v", err)
-	}
 
-	var translations []*models.Translation
-	if err := r.DB.Preload("Examples").Where("word_id = ?", word.ID).Find(&translations).Error; err != nil {
-		return nil, fmt.Errorf("could not fetch translations: %v", err)
-	}
+		if targetTerm != nil {
+			target, created, err := findOrCreateTerm(tx, *targetTerm, translation.TargetWord.LanguageCode)
+			if err != nil {
+				return apperrors.NewInternal(err)
+			}
+			if created {
+				log.created(model.RevisionEntityWord, target.ID)
+			}
 
-	var gqlTranslations []*model.Translation
-	for _, translation := range translations {
-		// Convert translation to GraphQL format
-		gqlTranslations = append(gqlTranslations, ToGraphQLTranslation(translation))
-	}
+			var count int64
+			if err := tx.Model(&models.Translation{}).
+				Where("word_id = ? AND target_word_id = ? AND id <> ?", translation.WordID, target.ID, translation.ID).
+				Count(&count).Error; err != nil {
+				return apperrors.NewInternal(err)
+			}
+			if count > 0 {
+				return apperrors.NewAlreadyExists(termField, "translation already exists: %s", *targetTerm)
+			}
 
-	return gqlTranslations, nil
-}
+			// Updating through the translation itself refreshes its updatedAt, the loaded words are not saved again
+			if err := tx.Model(&translation).Omit(clause.Associations).Update("target_word_id", target.ID).Error; err != nil {
+				return apperrors.FromDB(err, termField, "translation already exists: %s", *targetTerm)
+			}
+			translation.TargetWord = target
+		}
 
-// Examples retrieves examples by EnglishWord.
-func (r *queryResolver) Examples(ctx context.Context, polishWord string, englishWord string) ([]*model.Example, error) {
+		// The labels and the target term are one change, so the version is bumped once
+		if err := log.record(); err != nil {
+			return err
+		}
+		translation.Version = log.version(model.RevisionEntityTranslation, translation.ID, translation.Version)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err // triggers rollback
+	}
+
+	return ToGraphQLTranslation(&translation), nil
+}
+
+// UpdateExample replaces the sentence of an example or its parallel sentence.
+// Old sentences are left untouched, since examples of other translations may share them.
+func (r *mutationResolver) UpdateExample(ctx context.Context, id string, sentence *string, parallelSentence *string, validation *model.ExampleValidation, expectedVersion *int32) (*model.Example, error) {
+	exampleID, err := fromGlobalID("id", id, exampleType)
+	if err != nil {
+		return nil, err
+	}
+	if sentence == nil && parallelSentence == nil {
+		return nil, apperrors.NewValidation("sentence", "either sentence or parallelSentence is required")
+	}
+
+	var example models.Example
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		if err := withSentences(tx).First(&example, "examples.id = ?", exampleID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return apperrors.NewNotFound("id", "example not found: %s", id)
+			}
+			return apperrors.NewInternal(err)
+		}
+
+		if err := checkVersion(tx, model.RevisionEntityExample, example.ID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "updateExample")
+		if err := log.track(model.RevisionEntityExample, example.ID); err != nil {
+			return err
+		}
+
+		var translation models.Translation
+		if err := withTerms(tx).First(&translation, "translations.id = ?", example.TranslationID).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+
+		if sentence != nil {
+			exampleSentence, err := findOrCreateSentence(tx, *sentence)
+			if err != nil {
+				return apperrors.NewInternal(err)
+			}
+
+			var count int64
+			if err := tx.Model(&models.Example{}).
+				Where("translation_id = ? AND sentence_id = ? AND id <> ?", example.TranslationID, exampleSentence.ID, example.ID).
+				Count(&count).Error; err != nil {
+				return apperrors.NewInternal(err)
+			}
+			if count > 0 {
+				return apperrors.NewAlreadyExists("sentence", "example already exists: %s", *sentence)
+			}
+
+			example.SentenceID = exampleSentence.ID
+			example.Sentence = exampleSentence
+			// Only a new sentence is checked, the flag of the old one stays otherwise
+			if err := r.exampleValidator(validation)(tx, translation, &example); err != nil {
+				return err
+			}
+		}
+
+		if parallelSentence != nil {
+			if err := setParallelSentence(tx, &example, *parallelSentence, otherLanguage(translation, example)); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&example).Omit(clause.Associations).Updates(map[string]interface{}{
+			"sentence_id":            example.SentenceID,
+			"parallel_sentence_id":   example.ParallelSentenceID,
+			"parallel_language_code": example.ParallelLanguageCode,
+			"flagged":                example.Flagged,
+		}).Error; err != nil {
+			return apperrors.FromDB(err, "sentence", "example already exists: %s", example.Sentence.Text)
+		}
+
+		if err := log.record(); err != nil {
+			return err
+		}
+		example.Version = log.version(model.RevisionEntityExample, example.ID, example.Version)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err // triggers rollback
+	}
+
+	return ToGraphQLExample(&example), nil
+}
+
+// CreateInflection adds an inflected form to a word found either by wordId or by polishWord.
+func (r *mutationResolver) CreateInflection(ctx context.Context, polishWord *string, wordID *string, form string, grammaticalCase *model.GrammaticalCase, number *model.GrammaticalNumber, person *model.GrammaticalPerson) (*model.Inflection, error) {
+	if strings.TrimSpace(form) == "" {
+		return nil, apperrors.NewValidation("form", "form must not be empty")
+	}
+
+	var inflection models.Inflection
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		word, err := findWordByKey(tx, "wordId", wordID, polishWord)
+		if err != nil {
+			return err
+		}
+
+		inflection = models.Inflection{WordID: word.ID, Form: form}
+		if err := setInflectionCategories(&inflection, grammaticalCase, number, person); err != nil {
+			return err
+		}
+
+		// The unique_inflection index rejects a second form for the same categories
+		if err := tx.Create(&inflection).Error; err != nil {
+			return apperrors.FromDB(err, "form", "inflection already exists for these categories: %s", form)
+		}
+
+		log := newRevisionLog(tx, "createInflection")
+		log.created(model.RevisionEntityInflection, inflection.ID)
+		return log.record()
+	})
+
+	if err != nil {
+		return nil, err // triggers rollback
+	}
+
+	return ToGraphQLInflection(&inflection), nil
+}
+
+// UpdateInflection changes the form or the grammatical categories of an inflection.
+// Omitted arguments are left unchanged.
+func (r *mutationResolver) UpdateInflection(ctx context.Context, id string, form *string, grammaticalCase *model.GrammaticalCase, number *model.GrammaticalNumber, person *model.GrammaticalPerson, expectedVersion *int32) (*model.Inflection, error) {
+	inflectionID, err := fromGlobalID("id", id, inflectionType)
+	if err != nil {
+		return nil, err
+	}
+	if form != nil && strings.TrimSpace(*form) == "" {
+		return nil, apperrors.NewValidation("form", "form must not be empty")
+	}
+
+	var inflection models.Inflection
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		if err := liveInflections(tx).First(&inflection, inflectionID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return apperrors.NewNotFound("id", "inflection not found: %s", id)
+			}
+			return apperrors.NewInternal(err)
+		}
+
+		if err := checkVersion(tx, model.RevisionEntityInflection, inflection.ID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "updateInflection")
+		if err := log.track(model.RevisionEntityInflection, inflection.ID); err != nil {
+			return err
+		}
+
+		if form != nil {
+			inflection.Form = *form
+		}
+		if err := setInflectionCategories(&inflection, grammaticalCase, number, person); err != nil {
+			return err
+		}
+
+		if err := tx.Omit("version").Save(&inflection).Error; err != nil {
+			return apperrors.FromDB(err, "form", "inflection already exists for these categories: %s", inflection.Form)
+		}
+
+		if err := log.record(); err != nil {
+			return err
+		}
+		inflection.Version = log.version(model.RevisionEntityInflection, inflection.ID, inflection.Version)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err // triggers rollback
+	}
+
+	return ToGraphQLInflection(&inflection), nil
+}
+
+// DeleteInflection is the resolver for the deleteInflection field.
+func (r *mutationResolver) DeleteInflection(ctx context.Context, id string, expectedVersion *int32) (bool, error) {
+	inflectionID, err := fromGlobalID("id", id, inflectionType)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		// The inflections of a deleted word are kept for its restore
+		var inflection models.Inflection
+		if err := liveInflections(tx).First(&inflection, inflectionID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return apperrors.NewNotFound("id", "inflection not found: %s", id)
+			}
+			return apperrors.NewInternal(err)
+		}
+
+		if err := checkVersion(tx, model.RevisionEntityInflection, inflection.ID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "deleteInflection")
+		if err := log.track(model.RevisionEntityInflection, inflection.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&inflection).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+
+		return log.record()
+	})
+
+	if err != nil {
+		return false, err // triggers rollback
+	}
+
+	return true, nil
+}
+
+// LinkWords relates two words of the same language, e.g. "duży" and "wielki" as synonyms.
+func (r *mutationResolver) LinkWords(ctx context.Context, wordID *string, polishWord *string, relatedWordID *string, relatedPolishWord *string, typeArg model.RelationType) (*model.WordRelation, error) {
+	var relation models.WordRelation
+	var word models.Word
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		var related models.Word
+		var err error
+		word, related, err = findRelationWords(tx, wordID, polishWord, relatedWordID, relatedPolishWord)
+		if err != nil {
+			return err
+		}
+
+		relation, err = linkWords(tx, word, related, typeArg)
+		if err != nil {
+			return err
+		}
+
+		log := newRevisionLog(tx, "linkWords")
+		log.created(model.RevisionEntityWordRelation, relation.ID)
+		return log.record()
+	})
+
+	if err != nil {
+		return nil, err // triggers rollback
+	}
+
+	return ToGraphQLWordRelation(&relation, word.ID), nil
+}
+
+// UnlinkWords removes a relation between two words.
+func (r *mutationResolver) UnlinkWords(ctx context.Context, wordID *string, polishWord *string, relatedWordID *string, relatedPolishWord *string, typeArg model.RelationType) (bool, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		word, related, err := findRelationWords(tx, wordID, polishWord, relatedWordID, relatedPolishWord)
+		if err != nil {
+			return err
+		}
+
+		relation, err := findRelation(tx, word, related, typeArg)
+		if err != nil {
+			return err
+		}
+
+		log := newRevisionLog(tx, "unlinkWords")
+		if err := log.track(model.RevisionEntityWordRelation, relation.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&relation).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+		return log.record()
+	})
+
+	if err != nil {
+		return false, err // triggers rollback
+	}
+
+	return true, nil
+}
+
+// DeleteWord is the resolver for the deleteWord field.
+func (r *mutationResolver) DeleteWord(ctx context.Context, polishWord *string, id *string, expectedVersion *int32) (bool, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		word, err := findWordByKey(tx, "id", id, polishWord)
+		if err != nil {
+			return err
+		}
+
+		if err := checkVersion(tx, model.RevisionEntityWord, word.ID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "deleteWord")
+		if err := log.trackWord(word.ID); err != nil {
+			return err
+		}
+
+		if err := deleteWord(tx, word); err != nil {
+			return err
+		}
+		return log.record()
+	})
+
+	if err != nil {
+		return false, err // triggers rollback
+	}
+
+	return true, nil
+}
+
+// DeleteTranslation is the resolver for the deleteTranslation field.
+func (r *mutationResolver) DeleteTranslation(ctx context.Context, polishWord *string, englishWord *string, id *string, expectedVersion *int32) (bool, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		translation, err := findTranslationByKey(tx, "id", id, polishWord, englishWord)
+		if err != nil {
+			return err
+		}
+
+		if err := checkVersion(tx, model.RevisionEntityTranslation, translation.ID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "deleteTranslation")
+		if err := log.trackTranslations(translation.ID); err != nil {
+			return err
+		}
+
+		translationIDs := tx.Model(&models.Translation{}).Select("id").Where("id = ?", translation.ID)
+		if err := deleteTranslations(tx, translationIDs, tx.NowFunc()); err != nil {
+			return err
+		}
+		return log.record()
+	})
+
+	if err != nil {
+		return false, err // triggers rollback
+	}
+
+	return true, nil
+}
+
+// DeleteExample is the resolver for the deleteExample field.
+func (r *mutationResolver) DeleteExample(ctx context.Context, polishWord *string, englishWord *string, exampleSentence *string, id *string, expectedVersion *int32) (bool, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		example, err := findExampleByKey(tx, "id", id, polishWord, englishWord, exampleSentence)
+		if err != nil {
+			return err
+		}
+
+		if err := checkVersion(tx, model.RevisionEntityExample, example.ID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "deleteExample")
+		if err := log.track(model.RevisionEntityExample, example.ID); err != nil {
+			return err
+		}
+
+		// Only the link goes to the trash, the sentence may still illustrate other translations
+		if err := tx.Delete(&example).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+		return log.record()
+	})
+
+	if err != nil {
+		return false, err // triggers rollback
+	}
+
+	return true, nil
+}
+
+// RestoreWord brings back a deleted word with the translations and examples deleted along with it.
+func (r *mutationResolver) RestoreWord(ctx context.Context, id string, expectedVersion *int32) (*model.Word, error) {
+	wordID, err := fromGlobalID("id", id, wordType)
+	if err != nil {
+		return nil, err
+	}
+
+	var word models.Word
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkVersion(tx, model.RevisionEntityWord, wordID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "restoreWord")
+		if err := log.trackWord(wordID); err != nil {
+			return err
+		}
+
+		var err error
+		word, err = restoreWord(tx, "id", id)
+		if err != nil {
+			return err
+		}
+		if err := log.record(); err != nil {
+			return err
+		}
+		word.Version = log.version(model.RevisionEntityWord, word.ID, word.Version)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err // triggers rollback
+	}
+
+	return ToGraphQLWord(&word), nil
+}
+
+// RestoreTranslation brings back a deleted translation with the examples deleted along with it.
+func (r *mutationResolver) RestoreTranslation(ctx context.Context, id string, expectedVersion *int32) (*model.Translation, error) {
+	translationID, err := fromGlobalID("id", id, translationType)
+	if err != nil {
+		return nil, err
+	}
+
+	var translation models.Translation
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkVersion(tx, model.RevisionEntityTranslation, translationID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "restoreTranslation")
+		if err := log.trackTranslations(translationID); err != nil {
+			return err
+		}
+
+		var err error
+		translation, err = restoreTranslation(tx, "id", id)
+		if err != nil {
+			return err
+		}
+		if err := log.record(); err != nil {
+			return err
+		}
+		translation.Version = log.version(model.RevisionEntityTranslation, translation.ID, translation.Version)
+		return nil
+	})
 
-	var word models.Word
-	if err := r.DB.Where("polish_word = ?", polishWord).First(&word).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("word not found: %v", err)
+	if err != nil {
+		return nil, err // triggers rollback
+	}
+
+	return ToGraphQLTranslation(&translation), nil
+}
+
+// RestoreExample brings back a deleted example.
+func (r *mutationResolver) RestoreExample(ctx context.Context, id string, expectedVersion *int32) (*model.Example, error) {
+	exampleID, err := fromGlobalID("id", id, exampleType)
+	if err != nil {
+		return nil, err
+	}
+
+	var example models.Example
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkVersion(tx, model.RevisionEntityExample, exampleID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "restoreExample")
+		if err := log.track(model.RevisionEntityExample, exampleID); err != nil {
+			return err
+		}
+
+		var err error
+		example, err = restoreExample(tx, "id", id)
+		if err != nil {
+			return err
+		}
+		if err := log.record(); err != nil {
+			return err
+		}
+		example.Version = log.version(model.RevisionEntityExample, example.ID, example.Version)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err // triggers rollback
+	}
+
+	return ToGraphQLExample(&example), nil
+}
+
+// RevertToRevision brings the entity changed by a revision back to its state right after that revision.
+// The revert is recorded like any other change, so it can be reverted in turn.
+func (r *mutationResolver) RevertToRevision(ctx context.Context, revisionID string, expectedVersion *int32) ([]*model.Revision, error) {
+	var revisions []models.Revision
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		revision, err := findRevision(tx, "revisionId", revisionID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkVersion(tx, model.RevisionEntity(revision.EntityType), revision.EntityID, expectedVersion); err != nil {
+			return err
+		}
+		log := newRevisionLog(tx, "revertToRevision")
+		if err := revertToRevision(tx, log, "revisionId", revision); err != nil {
+			return err
+		}
+		if err := log.record(); err != nil {
+			return err
+		}
+		revisions = log.revisions
+		return nil
+	})
+
+	if err != nil {
+		return nil, err // triggers rollback
+	}
+
+	gqlRevisions := make([]*model.Revision, 0, len(revisions))
+	for i := range revisions {
+		gqlRevisions = append(gqlRevisions, ToGraphQLRevision(&revisions[i]))
+	}
+	return gqlRevisions, nil
+}
+
+// Node fetches any entity implementing the Node interface by its global ID.
+func (r *queryResolver) Node(ctx context.Context, id string) (model.Node, error) {
+	return loadNode(r.DB.WithContext(ctx), "id", id)
+}
+
+// Nodes fetches several entities by their global IDs, keeping the order of the ids.
+// IDs of entities which do not exist resolve to null instead of failing the whole list.
+func (r *queryResolver) Nodes(ctx context.Context, ids []string) ([]model.Node, error) {
+	nodes := make([]model.Node, 0, len(ids))
+	for _, id := range ids {
+		node, err := loadNode(r.DB.WithContext(ctx), "ids", id)
+		if err != nil {
+			var appErr *apperrors.Error
+			if !errors.As(err, &appErr) || appErr.Code != apperrors.NotFound {
+				return nil, err
+			}
 		}
-		return nil, fmt.Errorf("an error occurred: %v", err)
+		nodes = append(nodes, node)
 	}
 
-	var translation models.Translation
-	if err := r.DB.Where("word_id = ? AND english_word = ?", word.ID, englishWord).First(&translation).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	return nodes, nil
+}
+
+// Lookup retrieves the translations of a term in any language, optionally only those with the given usage labels.
+func (r *queryResolver) Lookup(ctx context.Context, term string, sourceLanguage string, targetLanguage *string, register *model.Register, domain *string, region *model.Region) ([]*model.Translation, error) {
+	if err := validateLanguage("sourceLanguage", sourceLanguage); err != nil {
+		return nil, err
+	}
+	if targetLanguage != nil {
+		if err := validateLanguage("targetLanguage", *targetLanguage); err != nil {
 			return nil, err
 		}
-		return nil, fmt.Errorf("an error occurred: %v", err)
 	}
 
-	var examples []*models.Example
-	if err := r.DB.Where("translation_id = ?", translation.ID).Find(&examples).Error; err != nil {
+	labels := labelFilter{Register: register, Domain: domain, Region: region}
+	return lookupTranslations(r.DB.WithContext(ctx), r.lemmatizerFor(sourceLanguage), "term", term, sourceLanguage, targetLanguage, labels)
+}
+
+// ReverseLookup retrieves the translations leading to a term in any language.
+func (r *queryResolver) ReverseLookup(ctx context.Context, term string, targetLanguage string, sourceLanguage *string) ([]*model.Translation, error) {
+	db := r.DB.WithContext(ctx)
+
+	if err := validateLanguage("targetLanguage", targetLanguage); err != nil {
+		return nil, err
+	}
+	if sourceLanguage != nil {
+		if err := validateLanguage("sourceLanguage", *sourceLanguage); err != nil {
+			return nil, err
+		}
+	}
+
+	target, err := findTerm(db, "term", "word", term, targetLanguage)
+	if err != nil {
+		return nil, err
+	}
+
+	translations, err := findReverseTranslations(db, target.ID, sourceLanguage)
+	if err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
+	gqlTranslations := make([]*model.Translation, 0, len(translations))
+	for _, translation := range translations {
+		gqlTranslations = append(gqlTranslations, ToGraphQLTranslation(translation))
+	}
+
+	return gqlTranslations, nil
+}
+
+// Words is the resolver for the words field.
+func (r *queryResolver) Words(ctx context.Context, filter *model.WordFilter) ([]*model.Word, error) {
+
+	var words []*models.Word
+	if err := filterWords(r.DB.WithContext(ctx), filter).Find(&words).Error; err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
+	var gqlWords []*model.Word
+	for _, word := range words {
+		gqlWords = append(gqlWords, ToGraphQLWord(word))
+	}
+
+	return gqlWords, nil
+}
+
+// WordsConnection retrieves one page of words using keyset (cursor) pagination.
+func (r *queryResolver) WordsConnection(ctx context.Context, first *int32, after *string, last *int32, before *string, orderBy *model.WordOrderField, filter *model.WordFilter) (*model.WordConnection, error) {
+
+	order := model.WordOrderFieldID
+	if orderBy != nil {
+		order = *orderBy
+	}
+
+	size, err := pageSize(first, last)
+	if err != nil {
+		return nil, err
+	}
+	backward := last != nil
+
+	db := r.DB.WithContext(ctx)
+	query := filterWords(db.Model(&models.Word{}), filter)
+	var afterCursor, beforeCursor *wordCursor
+	if after != nil {
+		c, err := decodeWordCursor(*after, "after", order)
+		if err != nil {
+			return nil, err
+		}
+		query = afterWordCursor(query, c, order, false)
+		afterCursor = &c
+	}
+	if before != nil {
+		c, err := decodeWordCursor(*before, "before", order)
+		if err != nil {
+			return nil, err
+		}
+		query = afterWordCursor(query, c, order, true)
+		beforeCursor = &c
+	}
+
+	// One extra row tells whether another page exists
+	var words []*models.Word
+	if err := orderWords(query, order, backward).
+		Limit(size + 1).
+		Find(&words).Error; err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
+	hasMore := len(words) > size
+	if hasMore {
+		words = words[:size]
+	}
+
+	// Backward pages are fetched in reverse, restore the requested order
+	if backward {
+		for i, j := 0, len(words)-1; i < j; i, j = i+1, j-1 {
+			words[i], words[j] = words[j], words[i]
+		}
+	}
+
+	// The extra row tells about the side the page is fetched towards, the other side needs its own query
+	hasNext, hasPrevious := hasMore, false
+	if backward {
+		hasNext, hasPrevious = false, hasMore
+	}
+	if !backward && afterCursor != nil {
+		if hasPrevious, err = wordsOutsideCursor(filterWords(db.Model(&models.Word{}), filter), *afterCursor, order, false); err != nil {
+			return nil, apperrors.NewInternal(err)
+		}
+	}
+	if backward && beforeCursor != nil {
+		if hasNext, err = wordsOutsideCursor(filterWords(db.Model(&models.Word{}), filter), *beforeCursor, order, true); err != nil {
+			return nil, apperrors.NewInternal(err)
+		}
+	}
+
+	connection := &model.WordConnection{
+		Edges: make([]*model.WordEdge, 0, len(words)),
+		PageInfo: &model.PageInfo{
+			HasNextPage:     hasNext,
+			HasPreviousPage: hasPrevious,
+		},
+	}
+	for _, word := range words {
+		connection.Edges = append(connection.Edges, &model.WordEdge{
+			Cursor: encodeWordCursor(word, order),
+			Node:   ToGraphQLWord(word),
+		})
+	}
+	if len(connection.Edges) > 0 {
+		connection.PageInfo.StartCursor = &connection.Edges[0].Cursor
+		connection.PageInfo.EndCursor = &connection.Edges[len(connection.Edges)-1].Cursor
+	}
+
+	return connection, nil
+}
+
+// Translations retrieves the English translations of a Polish word.
+// An inflected form such as "psa" is resolved to its word, either from the inflection tables or by the lemmatizer,
+// and matchedForm tells which form matched. register, domain and region keep only the translations with these labels.
+func (r *queryResolver) Translations(ctx context.Context, polishWord string, register *model.Register, domain *string, region *model.Region) ([]*model.Translation, error) {
+	english := languageEnglish
+	labels := labelFilter{Register: register, Domain: domain, Region: region}
+	return lookupTranslations(r.DB.WithContext(ctx), r.lemmatizer(), "polishWord", polishWord, languagePolish, &english, labels)
+}
+
+// SearchWords finds words matching the query, optionally ignoring Polish diacritics.
+func (r *queryResolver) SearchWords(ctx context.Context, query string, mode *model.SearchMode, foldDiacritics *bool, limit *int32, filter *model.WordFilter) ([]*model.Word, error) {
+	db := r.DB.WithContext(ctx)
+
+	if strings.TrimSpace(query) == "" {
+		return nil, apperrors.NewValidation("query", "search query must not be empty")
+	}
+
+	searchMode := model.SearchModeExact
+	if mode != nil {
+		searchMode = *mode
+	}
+	fold := foldDiacritics == nil || *foldDiacritics
+
+	size, err := limitSize(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var words []*models.Word
+	if err := filterWords(searchWordsQuery(db, query, searchMode, fold), filter).
+		Limit(size).
+		Find(&words).Error; err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
+	// Nothing matched as typed, an inflected query may still find its lemma
+	if len(words) == 0 {
+		var candidates []string
+		if lem := r.lemmatizerFor(filterLanguage(filter)); lem != nil {
+			candidates = lem.Lemmas(query)
+		}
+		if len(candidates) > 0 {
+			if err := filterWords(searchLemmasQuery(db, candidates, fold), filter).
+				Limit(size).
+				Find(&words).Error; err != nil {
+				return nil, apperrors.NewInternal(err)
+			}
+		}
+	}
+
+	gqlWords := make([]*model.Word, 0, len(words))
+	for _, word := range words {
+		gqlWords = append(gqlWords, ToGraphQLWord(word))
+	}
+
+	return gqlWords, nil
+}
+
+// Examples retrieves the examples of the English translation of a Polish word.
+func (r *queryResolver) Examples(ctx context.Context, polishWord string, englishWord string) ([]*model.Example, error) {
+	db := r.DB.WithContext(ctx)
+
+	word, err := findTerm(db, "polishWord", "word", polishWord, languagePolish)
+	if err != nil {
 		return nil, err
 	}
 
+	translation, err := findTranslation(db, word.ID, englishWord, languageEnglish)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, notFoundError(db, "englishWord", "translation", englishWord, languageEnglish)
+		}
+		return nil, apperrors.NewInternal(err)
+	}
+
+	var examples []*models.Example
+	if err := withSentences(db).Where("examples.translation_id = ?", translation.ID).Find(&examples).Error; err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
 	var gqlExamples []*model.Example
 	for _, example := range examples {
 		gqlExamples = append(gqlExamples, ToGraphQLExample(example))
@@ -445,14 +1449,179 @@ func (r *queryResolver) Examples(ctx context.Context, polishWord string, english
 	return gqlExamples, nil
 }
 
+// PolishWords retrieves every Polish word translated by EnglishWord.
+func (r *queryResolver) PolishWords(ctx context.Context, englishWord string) ([]*model.PolishTranslation, error) {
+	db := r.DB.WithContext(ctx)
+
+	englishTerm, err := findTerm(db, "englishWord", "english word", englishWord, languageEnglish)
+	if err != nil {
+		return nil, err
+	}
+
+	polish := languagePolish
+	translations, err := findReverseTranslations(db, englishTerm.ID, &polish)
+	if err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
+	gqlTranslations := make([]*model.PolishTranslation, 0, len(translations))
+	for _, translation := range translations {
+		gqlTranslations = append(gqlTranslations, ToGraphQLPolishTranslation(translation))
+	}
+
+	return gqlTranslations, nil
+}
+
+// Suggest retrieves stored terms similar to a possibly misspelled one, from every language unless languageCode is given.
+func (r *queryResolver) Suggest(ctx context.Context, term string, limit *int32, languageCode *string) ([]*model.Suggestion, error) {
+
+	if strings.TrimSpace(term) == "" {
+		return nil, apperrors.NewValidation("term", "term must not be empty")
+	}
+
+	var language string
+	if languageCode != nil {
+		if err := validateLanguage("languageCode", *languageCode); err != nil {
+			return nil, err
+		}
+		language = *languageCode
+	}
+
+	size, err := limitSize(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, err := suggestHeadwords(r.DB.WithContext(ctx), term, language, size)
+	if err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+
+	return suggestions, nil
+}
+
+// IdiomsContaining finds the entries of a kind, idioms by default, having the word among their components.
+func (r *queryResolver) IdiomsContaining(ctx context.Context, term string, languageCode *string, kind *model.EntryKind) ([]*model.Word, error) {
+	language := languageOrDefault(languageCode)
+	if err := validateLanguage("languageCode", language); err != nil {
+		return nil, err
+	}
+	entryKind := model.EntryKindIdiom
+	if kind != nil {
+		entryKind = *kind
+	}
+
+	entries, err := findEntriesContaining(r.DB.WithContext(ctx), r.lemmatizerFor(language), term, language, entryKind)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlWords := make([]*model.Word, 0, len(entries))
+	for i := range entries {
+		gqlWords = append(gqlWords, ToGraphQLWord(&entries[i]))
+	}
+	return gqlWords, nil
+}
+
+// LintExamples lists the stored examples whose sentence does not contain the word of its language.
+func (r *queryResolver) LintExamples(ctx context.Context, languageCode *string) ([]*model.ExampleViolation, error) {
+	if languageCode != nil {
+		if err := validateLanguage("languageCode", *languageCode); err != nil {
+			return nil, err
+		}
+	}
+	return r.lintExamples(r.DB.WithContext(ctx), languageCode)
+}
+
+// Trash lists the deleted words, translations and examples, most recently deleted first.
+func (r *queryResolver) Trash(ctx context.Context, since *time.Time, limit *int32) ([]*model.TrashItem, error) {
+	size, err := limitSize(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := findTrash(r.DB.WithContext(ctx), since, size)
+	if err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+	return items, nil
+}
+
+// History lists the changes of a word and of everything belonging to it, most recent first.
+func (r *queryResolver) History(ctx context.Context, wordID string) ([]*model.Revision, error) {
+	revisions, err := findHistory(r.DB.WithContext(ctx), "wordId", wordID)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlRevisions := make([]*model.Revision, 0, len(revisions))
+	for i := range revisions {
+		gqlRevisions = append(gqlRevisions, ToGraphQLRevision(&revisions[i]))
+	}
+	return gqlRevisions, nil
+}
+
+// Highlights is the resolver for the highlights field, batched per request by DataLoaders.
+func (r *exampleResolver) Highlights(ctx context.Context, obj *model.Example) ([]*model.Span, error) {
+	return r.highlights(ctx, obj)
+}
+
+// Examples is the resolver for the examples field of PolishTranslation.
+func (r *polishTranslationResolver) Examples(ctx context.Context, obj *model.PolishTranslation) ([]*model.Example, error) {
+	return loadExamples(ctx, r.DB, obj.ID)
+}
+
+// Examples is the resolver for the examples field, batched per request by a DataLoader.
+func (r *translationResolver) Examples(ctx context.Context, obj *model.Translation) ([]*model.Example, error) {
+	return loadExamples(ctx, r.DB, obj.ID)
+}
+
+// Inflections is the resolver for the inflections field, batched per request by a DataLoader.
+func (r *wordResolver) Inflections(ctx context.Context, obj *model.Word) ([]*model.Inflection, error) {
+	return loadInflections(ctx, r.DB, obj.ID)
+}
+
+// Translations is the resolver for the translations field, batched per request by a DataLoader.
+func (r *wordResolver) Translations(ctx context.Context, obj *model.Word, targetLanguage *string) ([]*model.Translation, error) {
+	return loadTranslations(ctx, r.DB, obj.ID, targetLanguage)
+}
+
+// Related is the resolver for the related field, batched per request by DataLoaders.
+func (r *wordResolver) Related(ctx context.Context, obj *model.Word, typeArg *model.RelationType) ([]*model.WordRelation, error) {
+	return loadRelated(ctx, r.DB, obj.ID, typeArg)
+}
+
+// Components is the resolver for the components field, batched per request by DataLoaders.
+func (r *wordResolver) Components(ctx context.Context, obj *model.Word) ([]*model.Word, error) {
+	return loadComponents(ctx, r.DB, obj.ID)
+}
+
+// Example returns generated1.ExampleResolver implementation.
+func (r *Resolver) Example() generated1.ExampleResolver { return &exampleResolver{r} }
+
 // Mutation returns generated1.MutationResolver implementation.
 func (r *Resolver) Mutation() generated1.MutationResolver { return &mutationResolver{r} }
 
 // Query returns generated1.QueryResolver implementation.
 func (r *Resolver) Query() generated1.QueryResolver { return &queryResolver{r} }
 
+// PolishTranslation returns generated1.PolishTranslationResolver implementation.
+func (r *Resolver) PolishTranslation() generated1.PolishTranslationResolver {
+	return &polishTranslationResolver{r}
+}
+
+// Translation returns generated1.TranslationResolver implementation.
+func (r *Resolver) Translation() generated1.TranslationResolver { return &translationResolver{r} }
+
+// Word returns generated1.WordResolver implementation.
+func (r *Resolver) Word() generated1.WordResolver { return &wordResolver{r} }
+
+type exampleResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type polishTranslationResolver struct{ *Resolver }
+type translationResolver struct{ *Resolver }
+type wordResolver struct{ *Resolver }
 
 // !!! WARNING !!!
 // The code below was going to be deleted when updating resolvers. It has been copied here so you have
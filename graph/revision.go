@@ -0,0 +1,768 @@
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Revisions are written by the mutations inside their own transaction, so a change and its revision
+// are committed or rolled back together. Snapshots hold global IDs along with the terms and sentences
+// these point at, so the history reads without further lookups. Timestamps are left out of them,
+// a mutation which only touches a row does not change it.
+
+type wordSnapshot struct {
+	ID           string  `json:"id"`
+	Term         string  `json:"term"`
+	LanguageCode string  `json:"languageCode"`
+	Kind         string  `json:"kind"`
+	PartOfSpeech *string `json:"partOfSpeech"`
+	Gender       *string `json:"gender"`
+	Aspect       *string `json:"aspect"`
+	Note         *string `json:"note"`
+	// Components are the global IDs of the words of a multi-word entry, in order
+	Components []string `json:"components"`
+	Deleted    bool     `json:"deleted"`
+}
+
+type translationSnapshot struct {
+	ID             string  `json:"id"`
+	WordID         string  `json:"wordID"`
+	SourceTerm     string  `json:"sourceTerm"`
+	TargetWordID   string  `json:"targetWordID"`
+	TargetTerm     string  `json:"targetTerm"`
+	TargetLanguage string  `json:"targetLanguage"`
+	Rank           int     `json:"rank"`
+	Register       *string `json:"register"`
+	Domain         *string `json:"domain"`
+	Region         *string `json:"region"`
+	Deleted        bool    `json:"deleted"`
+}
+
+type exampleSnapshot struct {
+	ID                   string  `json:"id"`
+	TranslationID        string  `json:"translationID"`
+	SentenceID           string  `json:"sentenceID"`
+	Sentence             string  `json:"sentence"`
+	LanguageCode         string  `json:"languageCode"`
+	ParallelSentenceID   *string `json:"parallelSentenceID"`
+	ParallelSentence     *string `json:"parallelSentence"`
+	ParallelLanguageCode *string `json:"parallelLanguageCode"`
+	Flagged              bool    `json:"flagged"`
+	Deleted              bool    `json:"deleted"`
+}
+
+type inflectionSnapshot struct {
+	ID              string  `json:"id"`
+	WordID          string  `json:"wordID"`
+	Form            string  `json:"form"`
+	GrammaticalCase *string `json:"grammaticalCase"`
+	Number          *string `json:"number"`
+	Person          *string `json:"person"`
+}
+
+type relationSnapshot struct {
+	ID            string `json:"id"`
+	WordID        string `json:"wordID"`
+	Term          string `json:"term"`
+	RelatedWordID string `json:"relatedWordID"`
+	RelatedTerm   string `json:"relatedTerm"`
+	Type          string `json:"type"`
+}
+
+// revisionEntityTypes maps the stored entity types to the type names of their global IDs
+var revisionEntityTypes = map[model.RevisionEntity]string{
+	model.RevisionEntityWord:         wordType,
+	model.RevisionEntityTranslation:  translationType,
+	model.RevisionEntityExample:      exampleType,
+	model.RevisionEntityInflection:   inflectionType,
+	model.RevisionEntityWordRelation: wordRelationType,
+}
+
+// versionedTables names the tables of the entities which count their changes in a version column.
+// Word relations are only linked and unlinked, so they have none.
+var versionedTables = map[model.RevisionEntity]string{
+	model.RevisionEntityWord:        "words",
+	model.RevisionEntityTranslation: "translations",
+	model.RevisionEntityExample:     "examples",
+	model.RevisionEntityInflection:  "inflections",
+}
+
+// entityState is the snapshot of an entity with the words whose history lists its changes.
+// A nil snapshot means the entity does not exist.
+type entityState struct {
+	wordID        uint
+	relatedWordID *uint
+	snapshot      *string
+	deleted       bool
+}
+
+type entityKey struct {
+	entityType model.RevisionEntity
+	id         uint
+}
+
+type trackedEntity struct {
+	entityKey
+	before entityState
+}
+
+// revisionLog collects the entities a mutation changes. They are tracked before the change and recorded
+// once the mutation is done, which writes a revision for each of them whose snapshot changed
+// and bumps its version, so a mutation changes the version of an entity at most once.
+type revisionLog struct {
+	tx       *gorm.DB
+	mutation string
+	tracked  []trackedEntity
+	seen     map[entityKey]bool
+	// revisions are the revisions written by record, versions the versions it bumped
+	revisions []models.Revision
+	versions  map[entityKey]int
+}
+
+func newRevisionLog(tx *gorm.DB, mutation string) *revisionLog {
+	return &revisionLog{tx: tx, mutation: mutation, seen: map[entityKey]bool{}, versions: map[entityKey]int{}}
+}
+
+// version returns the version of the entity after record, or the given one when record did not change it
+func (l *revisionLog) version(entityType model.RevisionEntity, id uint, version int) int {
+	if bumped, ok := l.versions[entityKey{entityType, id}]; ok {
+		return bumped
+	}
+	return version
+}
+
+// track remembers the current state of the entities. An entity tracked twice keeps its first state.
+func (l *revisionLog) track(entityType model.RevisionEntity, ids ...uint) error {
+	for _, id := range ids {
+		key := entityKey{entityType, id}
+		if l.seen[key] {
+			continue
+		}
+		before, err := loadEntityState(l.tx, entityType, id)
+		if err != nil {
+			return err
+		}
+		l.seen[key] = true
+		l.tracked = append(l.tracked, trackedEntity{entityKey: key, before: before})
+	}
+	return nil
+}
+
+// created tracks entities the mutation has just created, which did not exist before it
+func (l *revisionLog) created(entityType model.RevisionEntity, ids ...uint) {
+	for _, id := range ids {
+		key := entityKey{entityType, id}
+		if l.seen[key] {
+			continue
+		}
+		l.seen[key] = true
+		l.tracked = append(l.tracked, trackedEntity{entityKey: key})
+	}
+}
+
+// createdTranslation tracks a new translation together with the examples created along with it
+func (l *revisionLog) createdTranslation(translationID uint) error {
+	l.created(model.RevisionEntityTranslation, translationID)
+
+	var exampleIDs []uint
+	if err := l.tx.Model(&models.Example{}).Where("translation_id = ?", translationID).Order("id").Pluck("id", &exampleIDs).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+	l.created(model.RevisionEntityExample, exampleIDs...)
+	return nil
+}
+
+// trackWord tracks the word with all translations from or into it and their examples,
+// which deleting or restoring the word changes along with it
+func (l *revisionLog) trackWord(wordID uint) error {
+	if err := l.track(model.RevisionEntityWord, wordID); err != nil {
+		return err
+	}
+
+	var translationIDs []uint
+	if err := l.tx.Unscoped().Model(&models.Translation{}).
+		Where("word_id = ? OR target_word_id = ?", wordID, wordID).
+		Order("id").Pluck("id", &translationIDs).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+	return l.trackTranslations(translationIDs...)
+}
+
+// trackTranslations tracks the translations with all of their examples
+func (l *revisionLog) trackTranslations(translationIDs ...uint) error {
+	if len(translationIDs) == 0 {
+		return nil
+	}
+	if err := l.track(model.RevisionEntityTranslation, translationIDs...); err != nil {
+		return err
+	}
+
+	var exampleIDs []uint
+	if err := l.tx.Unscoped().Model(&models.Example{}).
+		Where("translation_id IN ?", translationIDs).
+		Order("id").Pluck("id", &exampleIDs).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+	return l.track(model.RevisionEntityExample, exampleIDs...)
+}
+
+// record writes a revision for every tracked entity whose snapshot changed, in the order they were tracked
+func (l *revisionLog) record() error {
+	for _, entity := range l.tracked {
+		after, err := loadEntityState(l.tx, entity.entityType, entity.id)
+		if err != nil {
+			return err
+		}
+		if sameSnapshot(entity.before.snapshot, after.snapshot) {
+			continue
+		}
+
+		// A deleted entity has no words left to tell which history it belongs to
+		owner := after
+		if after.snapshot == nil {
+			owner = entity.before
+		}
+		revision := models.Revision{
+			WordID:        owner.wordID,
+			RelatedWordID: owner.relatedWordID,
+			EntityType:    entity.entityType.String(),
+			EntityID:      entity.id,
+			Action:        revisionAction(entity.before, after).String(),
+			Mutation:      l.mutation,
+			Before:        entity.before.snapshot,
+			After:         after.snapshot,
+		}
+		if err := l.tx.Create(&revision).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+		l.revisions = append(l.revisions, revision)
+
+		// New rows start at version 1 and removed ones have none left
+		if entity.before.snapshot == nil || after.snapshot == nil {
+			continue
+		}
+		if err := l.bump(entity.entityType, entity.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bump increments the version of a stored entity, unless the mutation already did.
+// record bumps the entities it writes revisions for; a change the snapshots do not show bumps through it directly.
+func (l *revisionLog) bump(entityType model.RevisionEntity, id uint) error {
+	key := entityKey{entityType, id}
+	table, versioned := versionedTables[entityType]
+	if _, bumped := l.versions[key]; bumped || !versioned {
+		return nil
+	}
+	var version int
+	if err := l.tx.Raw("UPDATE "+table+" SET version = version + 1 WHERE id = ? RETURNING version", id).Scan(&version).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+	l.versions[key] = version
+	return nil
+}
+
+// revisionAction tells how an entity changed between the two states
+func revisionAction(before entityState, after entityState) model.RevisionAction {
+	switch {
+	case before.snapshot == nil:
+		return model.RevisionActionCreate
+	case after.snapshot == nil, after.deleted && !before.deleted:
+		return model.RevisionActionDelete
+	case before.deleted && !after.deleted:
+		return model.RevisionActionRestore
+	default:
+		return model.RevisionActionUpdate
+	}
+}
+
+func sameSnapshot(before *string, after *string) bool {
+	if before == nil || after == nil {
+		return before == after
+	}
+	return *before == *after
+}
+
+// loadEntityState reads the snapshot of an entity, deleted or not
+func loadEntityState(tx *gorm.DB, entityType model.RevisionEntity, id uint) (entityState, error) {
+	var state entityState
+	var snapshot interface{}
+	tx = tx.Unscoped()
+
+	switch entityType {
+	case model.RevisionEntityWord:
+		var words []models.Word
+		if err := tx.Where("id = ?", id).Find(&words).Error; err != nil {
+			return state, apperrors.NewInternal(err)
+		}
+		if len(words) == 0 {
+			return state, nil
+		}
+		word := words[0]
+
+		var componentIDs []uint
+		if err := tx.Model(&models.WordComponent{}).Where("word_id = ?", id).Order("position").Pluck("component_word_id", &componentIDs).Error; err != nil {
+			return state, apperrors.NewInternal(err)
+		}
+		components := make([]string, 0, len(componentIDs))
+		for _, componentID := range componentIDs {
+			components = append(components, toGlobalID(wordType, componentID))
+		}
+
+		state.wordID, state.deleted = word.ID, word.DeletedAt.Valid
+		snapshot = wordSnapshot{
+			ID:           toGlobalID(wordType, word.ID),
+			Term:         word.Term,
+			LanguageCode: word.LanguageCode,
+			Kind:         word.Kind,
+			PartOfSpeech: word.PartOfSpeech,
+			Gender:       word.Gender,
+			Aspect:       word.Aspect,
+			Note:         word.Note,
+			Components:   components,
+			Deleted:      word.DeletedAt.Valid,
+		}
+
+	case model.RevisionEntityTranslation:
+		var translations []models.Translation
+		if err := withTerms(tx).Where("translations.id = ?", id).Find(&translations).Error; err != nil {
+			return state, apperrors.NewInternal(err)
+		}
+		if len(translations) == 0 {
+			return state, nil
+		}
+		t := translations[0]
+
+		state.wordID, state.deleted = t.WordID, t.DeletedAt.Valid
+		snapshot = translationSnapshot{
+			ID:             toGlobalID(translationType, t.ID),
+			WordID:         toGlobalID(wordType, t.WordID),
+			SourceTerm:     t.Word.Term,
+			TargetWordID:   toGlobalID(wordType, t.TargetWordID),
+			TargetTerm:     t.TargetWord.Term,
+			TargetLanguage: t.TargetWord.LanguageCode,
+			Rank:           t.Rank,
+			Register:       t.Register,
+			Domain:         t.Domain,
+			Region:         t.Region,
+			Deleted:        t.DeletedAt.Valid,
+		}
+
+	case model.RevisionEntityExample:
+		var examples []models.Example
+		if err := withSentences(tx).Where("examples.id = ?", id).Find(&examples).Error; err != nil {
+			return state, apperrors.NewInternal(err)
+		}
+		if len(examples) == 0 {
+			return state, nil
+		}
+		e := examples[0]
+
+		// Examples belong to the source word of their translation
+		var wordIDs []uint
+		if err := tx.Model(&models.Translation{}).Where("id = ?", e.TranslationID).Pluck("word_id", &wordIDs).Error; err != nil {
+			return state, apperrors.NewInternal(err)
+		}
+		if len(wordIDs) > 0 {
+			state.wordID = wordIDs[0]
+		}
+
+		example := exampleSnapshot{
+			ID:                   toGlobalID(exampleType, e.ID),
+			TranslationID:        toGlobalID(translationType, e.TranslationID),
+			SentenceID:           toGlobalID(sentenceType, e.SentenceID),
+			Sentence:             e.Sentence.Text,
+			LanguageCode:         e.LanguageCode,
+			ParallelLanguageCode: e.ParallelLanguageCode,
+			Flagged:              e.Flagged,
+			Deleted:              e.DeletedAt.Valid,
+		}
+		if e.ParallelSentence != nil {
+			parallelSentenceID := toGlobalID(sentenceType, e.ParallelSentence.ID)
+			example.ParallelSentenceID = &parallelSentenceID
+			example.ParallelSentence = &e.ParallelSentence.Text
+		}
+		state.deleted = e.DeletedAt.Valid
+		snapshot = example
+
+	case model.RevisionEntityInflection:
+		var inflections []models.Inflection
+		if err := tx.Where("id = ?", id).Find(&inflections).Error; err != nil {
+			return state, apperrors.NewInternal(err)
+		}
+		if len(inflections) == 0 {
+			return state, nil
+		}
+		i := inflections[0]
+
+		state.wordID = i.WordID
+		snapshot = inflectionSnapshot{
+			ID:              toGlobalID(inflectionType, i.ID),
+			WordID:          toGlobalID(wordType, i.WordID),
+			Form:            i.Form,
+			GrammaticalCase: i.GrammaticalCase,
+			Number:          i.Number,
+			Person:          i.Person,
+		}
+
+	case model.RevisionEntityWordRelation:
+		var relations []models.WordRelation
+		if err := tx.Joins("Word").Joins("RelatedWord").Where("word_relations.id = ?", id).Find(&relations).Error; err != nil {
+			return state, apperrors.NewInternal(err)
+		}
+		if len(relations) == 0 {
+			return state, nil
+		}
+		r := relations[0]
+
+		relatedWordID := r.RelatedWordID
+		state.wordID, state.relatedWordID = r.WordID, &relatedWordID
+		snapshot = relationSnapshot{
+			ID:            toGlobalID(wordRelationType, r.ID),
+			WordID:        toGlobalID(wordType, r.WordID),
+			Term:          r.Word.Term,
+			RelatedWordID: toGlobalID(wordType, r.RelatedWordID),
+			RelatedTerm:   r.RelatedWord.Term,
+			Type:          r.Type,
+		}
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return state, apperrors.NewInternal(err)
+	}
+	encoded := string(data)
+	state.snapshot = &encoded
+	return state, nil
+}
+
+// findHistory lists the revisions of the word, including those of its relations stored from the other word,
+// most recent first. The word may be deleted, but it has to have existed.
+func findHistory(db *gorm.DB, field string, id string) ([]models.Revision, error) {
+	wordID, err := fromGlobalID(field, id, wordType)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := db.Unscoped().Model(&models.Word{}).Where("id = ?", wordID).Count(&count).Error; err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+	if count == 0 {
+		return nil, apperrors.NewNotFound(field, "word not found: %s", id)
+	}
+
+	var revisions []models.Revision
+	if err := db.Where("word_id = ? OR related_word_id = ?", wordID, wordID).Order("id DESC").Find(&revisions).Error; err != nil {
+		return nil, apperrors.NewInternal(err)
+	}
+	return revisions, nil
+}
+
+// findRevision fetches the revision behind a global ID
+func findRevision(tx *gorm.DB, field string, id string) (models.Revision, error) {
+	var revision models.Revision
+	revisionID, err := fromGlobalID(field, id, revisionType)
+	if err != nil {
+		return revision, err
+	}
+
+	if err := tx.First(&revision, revisionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return revision, apperrors.NewNotFound(field, "revision not found: %s", id)
+		}
+		return revision, apperrors.NewInternal(err)
+	}
+	return revision, nil
+}
+
+// revertToRevision brings the entity changed by the revision back to its snapshot right after it.
+// Deleting or restoring it also deletes or restores the rows which go along with it, as the delete and
+// restore mutations do. The log tracks everything the revert changes.
+func revertToRevision(tx *gorm.DB, log *revisionLog, field string, revision models.Revision) error {
+	entityType := model.RevisionEntity(revision.EntityType)
+	if revision.After != nil {
+		var snapshot interface{}
+		switch entityType {
+		case model.RevisionEntityWord:
+			snapshot = &wordSnapshot{}
+		case model.RevisionEntityTranslation:
+			snapshot = &translationSnapshot{}
+		case model.RevisionEntityExample:
+			snapshot = &exampleSnapshot{}
+		case model.RevisionEntityInflection:
+			snapshot = &inflectionSnapshot{}
+		case model.RevisionEntityWordRelation:
+			snapshot = &relationSnapshot{}
+		}
+		if err := json.Unmarshal([]byte(*revision.After), snapshot); err != nil {
+			return apperrors.NewInternal(err)
+		}
+
+		switch target := snapshot.(type) {
+		case *wordSnapshot:
+			return revertWord(tx, log, field, revision.EntityID, *target)
+		case *translationSnapshot:
+			return revertTranslation(tx, log, field, revision.EntityID, *target)
+		case *exampleSnapshot:
+			return revertExample(tx, log, field, revision.EntityID, *target)
+		case *inflectionSnapshot:
+			return revertInflection(tx, log, field, revision.EntityID, target)
+		case *relationSnapshot:
+			return revertRelation(tx, log, field, revision.EntityID, target)
+		}
+	}
+
+	// Only inflections and relations are removed for good, the other entities go to the trash
+	switch entityType {
+	case model.RevisionEntityInflection:
+		return revertInflection(tx, log, field, revision.EntityID, nil)
+	case model.RevisionEntityWordRelation:
+		return revertRelation(tx, log, field, revision.EntityID, nil)
+	}
+	return apperrors.NewInternal(fmt.Errorf("revision %d of a %s has no snapshot to revert to", revision.ID, entityType))
+}
+
+// storedWordID decodes the global ID of a word a snapshot points at, which has to be stored to be used again
+func storedWordID(tx *gorm.DB, field string, globalID string) (uint, error) {
+	wordID, err := fromGlobalID(field, globalID, wordType)
+	if err != nil {
+		return 0, apperrors.NewInternal(err)
+	}
+
+	var word models.Word
+	if err := tx.Unscoped().First(&word, wordID).Error; err != nil {
+		return 0, apperrors.NewInternal(err)
+	}
+	if word.DeletedAt.Valid {
+		return 0, apperrors.NewValidation(field, "the word %s is deleted, restore it first", word.Term)
+	}
+	return word.ID, nil
+}
+
+// revertWord brings the word back to the snapshot, with its grammar, kind and components
+func revertWord(tx *gorm.DB, log *revisionLog, field string, id uint, target wordSnapshot) error {
+	if err := log.trackWord(id); err != nil {
+		return err
+	}
+
+	var word models.Word
+	if err := tx.Unscoped().First(&word, id).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+
+	if target.Deleted {
+		if word.DeletedAt.Valid {
+			return nil
+		}
+		return deleteWord(tx, word)
+	}
+	if word.DeletedAt.Valid {
+		var err error
+		if word, err = restoreWord(tx, field, toGlobalID(wordType, id)); err != nil {
+			return err
+		}
+	}
+
+	word.Term, word.Kind = target.Term, target.Kind
+	word.PartOfSpeech, word.Gender, word.Aspect, word.Note = target.PartOfSpeech, target.Gender, target.Aspect, target.Note
+	if err := tx.Omit(clause.Associations, "version").Save(&word).Error; err != nil {
+		return apperrors.FromDB(err, field, "word already exists: %s", word.Term)
+	}
+
+	if err := tx.Where("word_id = ?", word.ID).Delete(&models.WordComponent{}).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+	for i, componentID := range target.Components {
+		componentWordID, err := storedWordID(tx, field, componentID)
+		if err != nil {
+			return err
+		}
+
+		link := models.WordComponent{WordID: word.ID, ComponentWordID: componentWordID, Position: i + 1}
+		if err := tx.Create(&link).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+	}
+	return nil
+}
+
+// revertTranslation brings the translation back to the snapshot, with its target term, rank and labels
+func revertTranslation(tx *gorm.DB, log *revisionLog, field string, id uint, target translationSnapshot) error {
+	if err := log.trackTranslations(id); err != nil {
+		return err
+	}
+
+	var translation models.Translation
+	if err := tx.Unscoped().First(&translation, id).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+
+	if target.Deleted {
+		if translation.DeletedAt.Valid {
+			return nil
+		}
+		translationIDs := tx.Model(&models.Translation{}).Select("id").Where("id = ?", id)
+		return deleteTranslations(tx, translationIDs, tx.NowFunc())
+	}
+	if translation.DeletedAt.Valid {
+		var err error
+		if translation, err = restoreTranslation(tx, field, toGlobalID(translationType, id)); err != nil {
+			return err
+		}
+	}
+
+	targetWordID, err := storedWordID(tx, field, target.TargetWordID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Model(&translation).Omit(clause.Associations).Updates(map[string]interface{}{
+		"target_word_id": targetWordID,
+		"rank":           target.Rank,
+		"register":       target.Register,
+		"domain":         target.Domain,
+		"region":         target.Region,
+	}).Error; err != nil {
+		return apperrors.FromDB(err, field, "translation already exists: %s", target.TargetTerm)
+	}
+	return nil
+}
+
+// revertExample brings the example back to the snapshot, with its sentences and flag
+func revertExample(tx *gorm.DB, log *revisionLog, field string, id uint, target exampleSnapshot) error {
+	if err := log.track(model.RevisionEntityExample, id); err != nil {
+		return err
+	}
+
+	var example models.Example
+	if err := tx.Unscoped().First(&example, id).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+
+	if target.Deleted {
+		if example.DeletedAt.Valid {
+			return nil
+		}
+		if err := tx.Delete(&example).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+		return nil
+	}
+	if example.DeletedAt.Valid {
+		var err error
+		if example, err = restoreExample(tx, field, toGlobalID(exampleType, id)); err != nil {
+			return err
+		}
+	}
+
+	// Sentences are never deleted, so the ones of the snapshot are still stored
+	sentenceID, err := fromGlobalID(field, target.SentenceID, sentenceType)
+	if err != nil {
+		return apperrors.NewInternal(err)
+	}
+	var parallelSentenceID *uint
+	if target.ParallelSentenceID != nil {
+		id, err := fromGlobalID(field, *target.ParallelSentenceID, sentenceType)
+		if err != nil {
+			return apperrors.NewInternal(err)
+		}
+		parallelSentenceID = &id
+	}
+
+	if err := tx.Model(&example).Omit(clause.Associations).Updates(map[string]interface{}{
+		"sentence_id":            sentenceID,
+		"language_code":          target.LanguageCode,
+		"parallel_sentence_id":   parallelSentenceID,
+		"parallel_language_code": target.ParallelLanguageCode,
+		"flagged":                target.Flagged,
+	}).Error; err != nil {
+		return apperrors.FromDB(err, field, "example already exists: %s", target.Sentence)
+	}
+	return nil
+}
+
+// revertInflection brings the inflection back to the snapshot, creating it again under its old ID
+// when it was deleted since. A nil snapshot deletes it. The word of the inflection has to be stored.
+func revertInflection(tx *gorm.DB, log *revisionLog, field string, id uint, target *inflectionSnapshot) error {
+	if err := log.track(model.RevisionEntityInflection, id); err != nil {
+		return err
+	}
+
+	if target == nil {
+		if err := tx.Delete(&models.Inflection{}, id).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+		return nil
+	}
+
+	wordID, err := storedWordID(tx, field, target.WordID)
+	if err != nil {
+		return err
+	}
+
+	var inflections []models.Inflection
+	if err := tx.Where("id = ?", id).Find(&inflections).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+	if len(inflections) == 0 {
+		inflections = append(inflections, models.Inflection{ID: id, WordID: wordID})
+	}
+
+	// An inflection created again starts over at version 1, record bumps the version of a stored one
+	inflection := inflections[0]
+	inflection.Form = target.Form
+	inflection.GrammaticalCase, inflection.Number, inflection.Person = target.GrammaticalCase, target.Number, target.Person
+	if err := tx.Omit("version").Save(&inflection).Error; err != nil {
+		return apperrors.FromDB(err, field, "inflection already exists for these categories: %s", target.Form)
+	}
+	return nil
+}
+
+// revertRelation brings the relation back when the snapshot has it, under its old ID,
+// or removes it when the revision removed it. Both words have to be stored.
+func revertRelation(tx *gorm.DB, log *revisionLog, field string, id uint, target *relationSnapshot) error {
+	if err := log.track(model.RevisionEntityWordRelation, id); err != nil {
+		return err
+	}
+
+	if target == nil {
+		if err := tx.Delete(&models.WordRelation{}, id).Error; err != nil {
+			return apperrors.NewInternal(err)
+		}
+		return nil
+	}
+
+	var count int64
+	if err := tx.Model(&models.WordRelation{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+	if count > 0 {
+		// Relations are never changed, only linked and unlinked
+		return nil
+	}
+
+	wordID, err := storedWordID(tx, field, target.WordID)
+	if err != nil {
+		return err
+	}
+	relatedWordID, err := storedWordID(tx, field, target.RelatedWordID)
+	if err != nil {
+		return err
+	}
+
+	relation := models.WordRelation{ID: id, WordID: wordID, RelatedWordID: relatedWordID, Type: target.Type}
+	if err := tx.Omit(clause.Associations).Create(&relation).Error; err != nil {
+		return apperrors.FromDB(err, field, "relation already exists: %s", relation.Type)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"strings"
+	"translatorapi/graph/model"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeEscaper escapes LIKE wildcards so user input is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// likePattern turns the search text into a LIKE pattern for the given mode
+func likePattern(text string, mode model.SearchMode) string {
+	escaped := likeEscaper.Replace(text)
+
+	switch mode {
+	case model.SearchModePrefix:
+		return escaped + "%"
+	case model.SearchModeContains:
+		return "%" + escaped + "%"
+	default:
+		return escaped
+	}
+}
+
+// searchWordsQuery filters words matching the text and ranks them.
+// Words matching as typed come first, then words matching only after folding diacritics.
+// Inside each group the exact headword wins, then shorter and alphabetically earlier words.
+func searchWordsQuery(db *gorm.DB, text string, mode model.SearchMode, foldDiacritics bool) *gorm.DB {
+	pattern := likePattern(text, mode)
+	query := db.Model(&models.Word{})
+
+	if !foldDiacritics {
+		return query.Where("term LIKE ?", pattern).
+			Order(orderByExpr("term = ? DESC, length(term), term", text))
+	}
+
+	folded := models.FoldPolish(text)
+	foldedPattern := likePattern(folded, mode)
+
+	return query.Where("term LIKE ? OR normalized_word LIKE ?", pattern, foldedPattern).
+		Order(orderByExpr(
+			"CASE WHEN term = ? THEN 0 WHEN term LIKE ? THEN 1 WHEN normalized_word = ? THEN 2 ELSE 3 END, length(term), term",
+			text, pattern, folded,
+		))
+}
+
+// searchLemmasQuery finds the words whose headword is one of the candidate lemmas.
+// Shorter and alphabetically earlier headwords come first, like in searchWordsQuery.
+func searchLemmasQuery(db *gorm.DB, candidates []string, foldDiacritics bool) *gorm.DB {
+	query := db.Model(&models.Word{})
+
+	if foldDiacritics {
+		folded := make([]string, 0, len(candidates))
+		for _, c := range candidates {
+			folded = append(folded, models.FoldPolish(c))
+		}
+		query = query.Where("normalized_word IN ?", folded)
+	} else {
+		query = query.Where("term IN ?", candidates)
+	}
+
+	return query.Order(orderByExpr("length(term), term"))
+}
+
+// orderByExpr builds an ORDER BY clause from an expression with bound parameters.
+// GORM only renders the expression, so it has to hold the complete ordering.
+func orderByExpr(sql string, vars ...interface{}) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{SQL: sql, Vars: vars, WithoutParentheses: true}}
+}
@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+)
+
+// defaultSuggestions is the number of headwords attached to not-found errors
+const defaultSuggestions = 5
+
+// suggestHeadwords returns the stored terms closest to the given one by trigram similarity.
+// Terms are compared without Polish diacritics, so "zolw" is close to "żółw".
+// An empty language searches the terms of every language.
+func suggestHeadwords(db *gorm.DB, term string, language string, limit int) ([]*model.Suggestion, error) {
+	// Terms left without any translation are not worth suggesting,
+	// except Polish headwords, which are often added before their translations.
+	// Deleted words and translations are skipped like in every other query
+	query := `SELECT term AS word, language_code, similarity(normalized_word, @folded) AS similarity
+		FROM words WHERE normalized_word % @folded AND deleted_at IS NULL
+		AND (language_code = @headwords OR EXISTS (
+			SELECT 1 FROM translations WHERE translations.deleted_at IS NULL
+			AND (translations.word_id = words.id OR translations.target_word_id = words.id)
+		))`
+	if language != "" {
+		query += " AND language_code = @language"
+	}
+
+	var suggestions []*model.Suggestion
+	err := db.Raw(query+" ORDER BY similarity DESC, word LIMIT @limit",
+		map[string]interface{}{
+			"folded":    models.FoldPolish(term),
+			"headwords": languagePolish,
+			"language":  language,
+			"limit":     limit,
+		}).Scan(&suggestions).Error
+
+	return suggestions, err
+}
+
+// notFoundError builds a NOT_FOUND error for the field carrying "did you mean" suggestions in its extensions.
+// Failing to compute suggestions must not hide the original error, so it only drops them.
+func notFoundError(db *gorm.DB, field string, what string, term string, language string) *apperrors.Error {
+	err := apperrors.NewNotFound(field, "%s not found: %s", what, term)
+
+	suggestions, suggestErr := suggestHeadwords(db, term, language, defaultSuggestions)
+	if suggestErr != nil {
+		return err
+	}
+
+	words := make([]string, 0, len(suggestions))
+	for _, s := range suggestions {
+		words = append(words, s.Word)
+	}
+
+	return err.WithExtension("suggestions", words)
+}
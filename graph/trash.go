@@ -0,0 +1,200 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+	"time"
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+	"translatorapi/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Soft deletes mark the rows deleted along with an entity with the same deleted_at as the entity itself.
+// Restoring it brings back the rows with that deleted_at, but not those deleted on their own before.
+
+// deleteWord soft-deletes the word with the translations from or into it and their examples
+func deleteWord(tx *gorm.DB, word models.Word) error {
+	deletedAt := tx.NowFunc()
+
+	translationIDs := tx.Model(&models.Translation{}).Select("id").Where("word_id = ? OR target_word_id = ?", word.ID, word.ID)
+	if err := deleteTranslations(tx, translationIDs, deletedAt); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&word).UpdateColumn("deleted_at", deletedAt).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+	return nil
+}
+
+// deleteTranslations soft-deletes the translations selected by the translationIDs subquery with their examples.
+// The examples go first, the subquery no longer finds the translations once they are deleted.
+func deleteTranslations(tx *gorm.DB, translationIDs *gorm.DB, deletedAt time.Time) error {
+	if err := tx.Model(&models.Example{}).Where("translation_id IN (?)", translationIDs).UpdateColumn("deleted_at", deletedAt).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+	if err := tx.Model(&models.Translation{}).Where("id IN (?)", translationIDs).UpdateColumn("deleted_at", deletedAt).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+	return nil
+}
+
+// restoreWord brings back the deleted word with the translations and examples deleted along with it.
+// Translations whose other word is still deleted stay in the trash until that word is restored.
+func restoreWord(tx *gorm.DB, field string, id string) (models.Word, error) {
+	var word models.Word
+	wordID, err := fromGlobalID(field, id, wordType)
+	if err != nil {
+		return word, err
+	}
+
+	if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&word, wordID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return word, apperrors.NewNotFound(field, "deleted word not found: %s", id)
+		}
+		return word, apperrors.NewInternal(err)
+	}
+	deletedAt := word.DeletedAt.Time
+
+	// The unique index only covers stored words, so an equal word added since conflicts here
+	if err := tx.Unscoped().Model(&word).UpdateColumn("deleted_at", nil).Error; err != nil {
+		return word, apperrors.FromDB(err, field, "word already exists: %s", word.Term)
+	}
+	word.DeletedAt = gorm.DeletedAt{}
+
+	storedWordIDs := tx.Model(&models.Word{}).Select("id")
+	translationIDs := tx.Unscoped().Model(&models.Translation{}).Select("id").
+		Where("deleted_at = ? AND (word_id = ? OR target_word_id = ?)", deletedAt, word.ID, word.ID).
+		Where("word_id IN (?) AND target_word_id IN (?)", storedWordIDs, storedWordIDs)
+	if err := restoreTranslations(tx, field, translationIDs, deletedAt); err != nil {
+		return word, err
+	}
+	return word, nil
+}
+
+// restoreTranslation brings back the deleted translation with the examples deleted along with it.
+// Both of its words have to be stored, a translation deleted with its word is restored through restoreWord.
+func restoreTranslation(tx *gorm.DB, field string, id string) (models.Translation, error) {
+	var translation models.Translation
+	translationID, err := fromGlobalID(field, id, translationType)
+	if err != nil {
+		return translation, err
+	}
+
+	// Unscoped also joins deleted words, which tells why the translation cannot be restored
+	if err := withTerms(tx.Unscoped()).
+		Where("translations.deleted_at IS NOT NULL").
+		First(&translation, "translations.id = ?", translationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return translation, apperrors.NewNotFound(field, "deleted translation not found: %s", id)
+		}
+		return translation, apperrors.NewInternal(err)
+	}
+	for _, word := range []models.Word{translation.Word, translation.TargetWord} {
+		if word.DeletedAt.Valid {
+			return translation, apperrors.NewValidation(field, "the word %s is deleted, restore it first", word.Term)
+		}
+	}
+
+	translationIDs := tx.Unscoped().Model(&models.Translation{}).Select("id").Where("id = ?", translation.ID)
+	if err := restoreTranslations(tx, field, translationIDs, translation.DeletedAt.Time); err != nil {
+		return translation, err
+	}
+	translation.DeletedAt = gorm.DeletedAt{}
+	return translation, nil
+}
+
+// restoreTranslations brings back the translations selected by the translationIDs subquery,
+// with their examples deleted at deletedAt. The examples go first, like in deleteTranslations.
+func restoreTranslations(tx *gorm.DB, field string, translationIDs *gorm.DB, deletedAt time.Time) error {
+	if err := tx.Unscoped().Model(&models.Example{}).
+		Where("deleted_at = ? AND translation_id IN (?)", deletedAt, translationIDs).
+		UpdateColumn("deleted_at", nil).Error; err != nil {
+		return apperrors.FromDB(err, field, "an example added since uses the same sentence")
+	}
+	if err := tx.Unscoped().Model(&models.Translation{}).
+		Where("id IN (?)", translationIDs).
+		UpdateColumn("deleted_at", nil).Error; err != nil {
+		return apperrors.FromDB(err, field, "a translation added since links the same words")
+	}
+	return nil
+}
+
+// restoreExample brings back the deleted example, whose translation has to be stored
+func restoreExample(tx *gorm.DB, field string, id string) (models.Example, error) {
+	var example models.Example
+	exampleID, err := fromGlobalID(field, id, exampleType)
+	if err != nil {
+		return example, err
+	}
+
+	if err := withSentences(tx.Unscoped()).
+		Where("examples.deleted_at IS NOT NULL").
+		First(&example, "examples.id = ?", exampleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return example, apperrors.NewNotFound(field, "deleted example not found: %s", id)
+		}
+		return example, apperrors.NewInternal(err)
+	}
+
+	var count int64
+	if err := tx.Model(&models.Translation{}).Where("id = ?", example.TranslationID).Count(&count).Error; err != nil {
+		return example, apperrors.NewInternal(err)
+	}
+	if count == 0 {
+		return example, apperrors.NewValidation(field, "the translation of the example is deleted, restore it first")
+	}
+
+	if err := tx.Unscoped().Model(&example).Omit(clause.Associations).UpdateColumn("deleted_at", nil).Error; err != nil {
+		return example, apperrors.FromDB(err, field, "example already exists: %s", example.Sentence.Text)
+	}
+	example.DeletedAt = gorm.DeletedAt{}
+	return example, nil
+}
+
+// findTrash lists up to limit deleted words, translations and examples, most recently deleted first.
+// Rows deleted together come parents first: the word, then its translations, then their examples.
+func findTrash(db *gorm.DB, since *time.Time, limit int) ([]*model.TrashItem, error) {
+	deleted := func(table string) *gorm.DB {
+		query := db.Unscoped().Where(table + ".deleted_at IS NOT NULL")
+		if since != nil {
+			query = query.Where(table+".deleted_at >= ?", *since)
+		}
+		return query.Order(table + ".deleted_at DESC, " + table + ".id").Limit(limit)
+	}
+
+	var words []models.Word
+	if err := deleted("words").Find(&words).Error; err != nil {
+		return nil, err
+	}
+	var translations []models.Translation
+	if err := withTerms(deleted("translations")).Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	var examples []models.Example
+	if err := withSentences(deleted("examples")).Find(&examples).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]*model.TrashItem, 0, len(words)+len(translations)+len(examples))
+	for i := range words {
+		items = append(items, &model.TrashItem{Node: ToGraphQLWord(&words[i]), DeletedAt: words[i].DeletedAt.Time})
+	}
+	for i := range translations {
+		items = append(items, &model.TrashItem{Node: ToGraphQLTranslation(&translations[i]), DeletedAt: translations[i].DeletedAt.Time})
+	}
+	for i := range examples {
+		items = append(items, &model.TrashItem{Node: ToGraphQLExample(&examples[i]), DeletedAt: examples[i].DeletedAt.Time})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"strings"
+	"translatorapi/apperrors"
+	"translatorapi/graph/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// checkVersion guards a change of the entity against concurrent edits. It locks the row for the rest of
+// the transaction and fails with CONFLICT when its version is not expectedVersion, the one the client read.
+// A concurrent change either committed before, and is seen here, or waits for the lock until this one is done.
+// Without expectedVersion nothing is checked nor locked, so the last write wins.
+//
+// Words, translations, examples and inflections keep the version in their version column. It starts at 1
+// and the revision log bumps it once for every mutation which changes the entity, so a client holding
+// an old version always gets CONFLICT, however many changes it missed.
+func checkVersion(tx *gorm.DB, entityType model.RevisionEntity, id uint, expectedVersion *int32) error {
+	if expectedVersion == nil {
+		return nil
+	}
+	what := strings.ToLower(strings.ReplaceAll(entityType.String(), "_", " "))
+	table, versioned := versionedTables[entityType]
+	if !versioned {
+		return apperrors.NewValidation("expectedVersion", "a %s has no version", what)
+	}
+	if *expectedVersion < 1 {
+		return apperrors.NewValidation("expectedVersion", "expectedVersion must be at least 1")
+	}
+
+	// Deleted rows are locked as well, restoring them is a change too
+	var versions []int
+	if err := tx.Table(table).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Pluck("version", &versions).Error; err != nil {
+		return apperrors.NewInternal(err)
+	}
+	if len(versions) == 0 {
+		return apperrors.NewConflict("expectedVersion", "the %s was removed since version %d", what, *expectedVersion)
+	}
+	if versions[0] != int(*expectedVersion) {
+		return apperrors.NewConflict("expectedVersion", "the %s was changed since version %d, its current version is %d", what, *expectedVersion, versions[0]).
+			WithExtension("currentVersion", versions[0])
+	}
+	return nil
+}
@@ -1,39 +1,500 @@
 -- Ensure tables are created
 CREATE TABLE IF NOT EXISTS words (
     id SERIAL PRIMARY KEY,
-    polish_word VARCHAR(255) NOT NULL
+    term VARCHAR(255) NOT NULL,
+    language_code VARCHAR(8) NOT NULL,
+    normalized_word VARCHAR(255) NOT NULL,
+    part_of_speech VARCHAR(32),
+    gender VARCHAR(32),
+    aspect VARCHAR(32),
+    note TEXT,
+    kind VARCHAR(16) NOT NULL DEFAULT 'WORD',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255),
+    version INT NOT NULL DEFAULT 1
 );
 
 CREATE TABLE IF NOT EXISTS translations (
     id SERIAL PRIMARY KEY,
     word_id INT REFERENCES words(id) ON DELETE CASCADE,
-    english_word VARCHAR(255) NOT NULL
+    target_word_id INT NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    rank INT NOT NULL,
+    register VARCHAR(32),
+    domain VARCHAR(64),
+    region VARCHAR(8),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255),
+    version INT NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS sentences (
+    id SERIAL PRIMARY KEY,
+    text TEXT NOT NULL
 );
 
 CREATE TABLE IF NOT EXISTS examples (
     id SERIAL PRIMARY KEY,
     translation_id INT REFERENCES translations(id) ON DELETE CASCADE,
-    sentence TEXT NOT NULL
+    sentence_id INT NOT NULL REFERENCES sentences(id) ON DELETE CASCADE,
+    language_code VARCHAR(8) NOT NULL,
+    parallel_sentence_id INT REFERENCES sentences(id) ON DELETE SET NULL,
+    parallel_language_code VARCHAR(8),
+    flagged BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255),
+    version INT NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS inflections (
+    id SERIAL PRIMARY KEY,
+    word_id INT NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    form VARCHAR(255) NOT NULL,
+    grammatical_case VARCHAR(32),
+    number VARCHAR(32),
+    person VARCHAR(32),
+    version INT NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS word_components (
+    id SERIAL PRIMARY KEY,
+    word_id INT NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    component_word_id INT NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    position INT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS word_relations (
+    id SERIAL PRIMARY KEY,
+    word_id INT NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    related_word_id INT NOT NULL REFERENCES words(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS revisions (
+    id SERIAL PRIMARY KEY,
+    word_id INT NOT NULL REFERENCES words(id),
+    related_word_id INT REFERENCES words(id),
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id INT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    mutation VARCHAR(64) NOT NULL,
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255)
 );
 
+-- Backfill the diacritic-free form of words created before normalized_word existed.
+-- translate() has to stay in sync with models.FoldPolish.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns WHERE table_name = 'words' AND column_name = 'normalized_word'
+    ) THEN
+        ALTER TABLE words ADD COLUMN normalized_word VARCHAR(255);
+        UPDATE words SET normalized_word = translate(lower(polish_word), 'ąćęłńóśźż', 'acelnoszz');
+        ALTER TABLE words ALTER COLUMN normalized_word SET NOT NULL;
+    END IF;
+END $$;
+
+-- Grammatical metadata added after the words table was first created
+ALTER TABLE words ADD COLUMN IF NOT EXISTS part_of_speech VARCHAR(32);
+ALTER TABLE words ADD COLUMN IF NOT EXISTS gender VARCHAR(32);
+ALTER TABLE words ADD COLUMN IF NOT EXISTS aspect VARCHAR(32);
+ALTER TABLE words ADD COLUMN IF NOT EXISTS note TEXT;
+
+-- Move translations that still store english_word inline to english_terms
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns WHERE table_name = 'translations' AND column_name = 'english_word'
+    ) THEN
+        CREATE TABLE IF NOT EXISTS english_terms (
+            id SERIAL PRIMARY KEY,
+            term VARCHAR(255) NOT NULL
+        );
+
+        INSERT INTO english_terms (term)
+        SELECT DISTINCT english_word FROM translations
+        WHERE english_word NOT IN (SELECT term FROM english_terms);
+
+        ALTER TABLE translations ADD COLUMN IF NOT EXISTS english_term_id INT REFERENCES english_terms(id) ON DELETE CASCADE;
+
+        UPDATE translations SET english_term_id = english_terms.id
+        FROM english_terms WHERE english_terms.term = translations.english_word;
+
+        ALTER TABLE translations ALTER COLUMN english_term_id SET NOT NULL;
+        ALTER TABLE translations DROP CONSTRAINT IF EXISTS unique_english_word;
+        -- Dropping the column also drops any global UNIQUE (english_word) left by older GORM tags
+        ALTER TABLE translations DROP COLUMN english_word;
+    END IF;
+END $$;
+
+-- Move examples that still store sentence inline to sentences
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns WHERE table_name = 'examples' AND column_name = 'sentence'
+    ) THEN
+        INSERT INTO sentences (text)
+        SELECT DISTINCT sentence FROM examples
+        WHERE sentence NOT IN (SELECT text FROM sentences);
+
+        ALTER TABLE examples ADD COLUMN IF NOT EXISTS sentence_id INT REFERENCES sentences(id) ON DELETE CASCADE;
+
+        UPDATE examples SET sentence_id = sentences.id
+        FROM sentences WHERE sentences.text = examples.sentence;
+
+        ALTER TABLE examples ALTER COLUMN sentence_id SET NOT NULL;
+        ALTER TABLE examples DROP CONSTRAINT IF EXISTS unique_sentence;
+        -- Dropping the column also drops any global UNIQUE (sentence) left by older GORM tags
+        ALTER TABLE examples DROP COLUMN sentence;
+    END IF;
+END $$;
+
+-- Generalize Polish words and English terms to (term, language_code) entries.
+-- Existing words become Polish entries, English terms become English entries
+-- and translations point at their English entry through target_word_id.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns WHERE table_name = 'words' AND column_name = 'polish_word'
+    ) THEN
+        ALTER TABLE words DROP CONSTRAINT IF EXISTS unique_polish_word;
+        DROP INDEX IF EXISTS idx_words_polish_word_id;
+        DROP INDEX IF EXISTS idx_words_polish_word_pattern;
+        DROP INDEX IF EXISTS idx_words_polish_word_trgm;
+        ALTER TABLE words RENAME COLUMN polish_word TO term;
+        ALTER TABLE words ADD COLUMN language_code VARCHAR(8) NOT NULL DEFAULT 'pl';
+        ALTER TABLE words ALTER COLUMN language_code DROP DEFAULT;
+    END IF;
+
+    IF EXISTS (
+        SELECT 1 FROM information_schema.tables WHERE table_name = 'english_terms'
+    ) THEN
+        INSERT INTO words (term, language_code, normalized_word)
+        SELECT term, 'en', translate(lower(term), 'ąćęłńóśźż', 'acelnoszz') FROM english_terms;
+
+        ALTER TABLE translations ADD COLUMN IF NOT EXISTS target_word_id INT REFERENCES words(id) ON DELETE CASCADE;
+
+        UPDATE translations SET target_word_id = words.id
+        FROM english_terms, words
+        WHERE english_terms.id = translations.english_term_id
+        AND words.language_code = 'en' AND words.term = english_terms.term;
+
+        ALTER TABLE translations ALTER COLUMN target_word_id SET NOT NULL;
+        ALTER TABLE translations DROP CONSTRAINT IF EXISTS unique_english_word;
+        DROP INDEX IF EXISTS idx_translations_english_term_id;
+        ALTER TABLE translations DROP COLUMN english_term_id;
+        DROP TABLE english_terms;
+    END IF;
+END $$;
+
+-- Tag examples created before bilingual examples with the source language of their translation
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns WHERE table_name = 'examples' AND column_name = 'language_code'
+    ) THEN
+        ALTER TABLE examples ADD COLUMN language_code VARCHAR(8);
+
+        UPDATE examples SET language_code = words.language_code
+        FROM translations, words
+        WHERE translations.id = examples.translation_id AND words.id = translations.word_id;
+
+        -- Examples without a translation cannot tell, they were Polish like every word back then
+        UPDATE examples SET language_code = 'pl' WHERE language_code IS NULL;
+
+        ALTER TABLE examples ALTER COLUMN language_code SET NOT NULL;
+    END IF;
+END $$;
+
+-- The sentence of an example translated into the other language of its translation
+ALTER TABLE examples ADD COLUMN IF NOT EXISTS parallel_sentence_id INT REFERENCES sentences(id) ON DELETE SET NULL;
+ALTER TABLE examples ADD COLUMN IF NOT EXISTS parallel_language_code VARCHAR(8);
+
+-- Examples stored in FLAG validation mode although their sentence does not use the translation
+ALTER TABLE examples ADD COLUMN IF NOT EXISTS flagged BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Rank the translations stored before ranking in the order they were added
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns WHERE table_name = 'translations' AND column_name = 'rank'
+    ) THEN
+        ALTER TABLE translations ADD COLUMN rank INT;
+
+        UPDATE translations SET rank = ranked.rank
+        FROM (
+            SELECT id, ROW_NUMBER() OVER (PARTITION BY word_id ORDER BY id) AS rank FROM translations
+        ) AS ranked
+        WHERE ranked.id = translations.id;
+
+        ALTER TABLE translations ALTER COLUMN rank SET NOT NULL;
+    END IF;
+END $$;
+
+-- Entries stored before entry kinds are words, or phrases when they have several words.
+-- The condition has to stay in sync with models.DefaultKind.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns WHERE table_name = 'words' AND column_name = 'kind'
+    ) THEN
+        ALTER TABLE words ADD COLUMN kind VARCHAR(16) NOT NULL DEFAULT 'WORD';
+        UPDATE words SET kind = 'PHRASE' WHERE btrim(term) ~ '\s';
+    END IF;
+END $$;
+
+-- Usage labels of translations: register, subject domain and region
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS register VARCHAR(32);
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS domain VARCHAR(64);
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS region VARCHAR(8);
+
+-- Audit timestamps, the author and the soft delete mark of words, translations and examples.
+-- Rows stored before auditing count as created when the column was added, by an unknown author
+ALTER TABLE words ADD COLUMN IF NOT EXISTS created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE words ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE words ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE words ADD COLUMN IF NOT EXISTS created_by VARCHAR(255);
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS created_by VARCHAR(255);
+ALTER TABLE examples ADD COLUMN IF NOT EXISTS created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE examples ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE examples ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE examples ADD COLUMN IF NOT EXISTS created_by VARCHAR(255);
+
+-- Versions count the changes of a row for optimistic locking, rows stored before start at 1
+ALTER TABLE words ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE examples ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE inflections ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
 -- Add unique constraints safely
-DO $$ 
+DO $$
 BEGIN
     IF NOT EXISTS (
-        SELECT 1 FROM pg_constraint WHERE conname = 'unique_polish_word'
+        SELECT 1 FROM pg_constraint WHERE conname = 'unique_sentence_text'
     ) THEN
-        ALTER TABLE words ADD CONSTRAINT unique_polish_word UNIQUE (polish_word);
+        ALTER TABLE sentences ADD CONSTRAINT unique_sentence_text UNIQUE (text);
     END IF;
+END $$;
 
+-- Deleted words, translations and examples stay in the trash until restored, so uniqueness only holds
+-- among the rows which are not deleted. Unique constraints of older databases become partial unique indexes
+DO $$
+BEGIN
     IF NOT EXISTS (
-        SELECT 1 FROM pg_constraint WHERE conname = 'unique_english_word'
+        SELECT 1 FROM pg_indexes WHERE indexname = 'unique_word_term' AND indexdef LIKE '%WHERE%'
     ) THEN
-        ALTER TABLE translations ADD CONSTRAINT unique_english_word UNIQUE (word_id, english_word);
+        ALTER TABLE words DROP CONSTRAINT IF EXISTS unique_word_term;
+        DROP INDEX IF EXISTS unique_word_term;
+        CREATE UNIQUE INDEX unique_word_term ON words (language_code, term) WHERE deleted_at IS NULL;
     END IF;
 
     IF NOT EXISTS (
-        SELECT 1 FROM pg_constraint WHERE conname = 'unique_sentence'
+        SELECT 1 FROM pg_indexes WHERE indexname = 'unique_translation' AND indexdef LIKE '%WHERE%'
     ) THEN
-        ALTER TABLE examples ADD CONSTRAINT unique_sentence UNIQUE (sentence, translation_id);
+        ALTER TABLE translations DROP CONSTRAINT IF EXISTS unique_translation;
+        DROP INDEX IF EXISTS unique_translation;
+        CREATE UNIQUE INDEX unique_translation ON translations (word_id, target_word_id) WHERE deleted_at IS NULL;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_indexes WHERE indexname = 'unique_sentence' AND indexdef LIKE '%WHERE%'
+    ) THEN
+        ALTER TABLE examples DROP CONSTRAINT IF EXISTS unique_sentence;
+        DROP INDEX IF EXISTS unique_sentence;
+        CREATE UNIQUE INDEX unique_sentence ON examples (sentence_id, translation_id) WHERE deleted_at IS NULL;
+    END IF;
+END $$;
+
+-- The trash lists deleted rows by the time they were deleted
+CREATE INDEX IF NOT EXISTS idx_words_deleted_at ON words (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_translations_deleted_at ON translations (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_examples_deleted_at ON examples (deleted_at);
+
+-- Revisions are append-only: rows cannot be changed or removed, and words with a history cannot be removed for good.
+-- Entity types and actions only take the values of the GraphQL enums
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'check_revision'
+    ) THEN
+        ALTER TABLE revisions ADD CONSTRAINT check_revision CHECK (
+            entity_type IN ('WORD', 'TRANSLATION', 'EXAMPLE', 'INFLECTION', 'WORD_RELATION')
+            AND action IN ('CREATE', 'UPDATE', 'DELETE', 'RESTORE')
+        );
+    END IF;
+END $$;
+
+CREATE OR REPLACE FUNCTION reject_revision_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'revisions are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS revisions_append_only ON revisions;
+CREATE TRIGGER revisions_append_only BEFORE UPDATE OR DELETE ON revisions
+    FOR EACH ROW EXECUTE FUNCTION reject_revision_change();
+
+-- The history of a word lists its revisions and those of relations stored from the other word, newest first
+CREATE INDEX IF NOT EXISTS idx_revisions_word_id ON revisions (word_id, id);
+CREATE INDEX IF NOT EXISTS idx_revisions_related_word_id ON revisions (related_word_id, id);
+
+-- Grammatical metadata only takes the values of the GraphQL enums
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'check_word_part_of_speech'
+    ) THEN
+        ALTER TABLE words ADD CONSTRAINT check_word_part_of_speech CHECK (part_of_speech IN (
+            'NOUN', 'VERB', 'ADJECTIVE', 'ADVERB', 'PRONOUN', 'NUMERAL',
+            'PREPOSITION', 'CONJUNCTION', 'PARTICLE', 'INTERJECTION'
+        ));
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'check_word_gender'
+    ) THEN
+        ALTER TABLE words ADD CONSTRAINT check_word_gender CHECK (
+            gender IS NULL OR (part_of_speech = 'NOUN' AND gender IN (
+                'MASCULINE_PERSONAL', 'MASCULINE_ANIMATE', 'MASCULINE_INANIMATE', 'FEMININE', 'NEUTER'
+            ))
+        );
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'check_word_aspect'
+    ) THEN
+        ALTER TABLE words ADD CONSTRAINT check_word_aspect CHECK (
+            aspect IS NULL OR (part_of_speech = 'VERB' AND aspect IN ('IMPERFECTIVE', 'PERFECTIVE'))
+        );
+    END IF;
+END $$;
+
+-- Translation labels only take the values of the GraphQL enums
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'check_translation_labels'
+    ) THEN
+        ALTER TABLE translations ADD CONSTRAINT check_translation_labels CHECK (
+            register IN ('FORMAL', 'COLLOQUIAL', 'VULGAR', 'ARCHAIC')
+            AND region IN ('UK', 'US')
+            AND domain <> ''
+        );
     END IF;
 END $$;
+
+-- A parallel sentence always has its language, which differs from the language of the sentence
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'check_example_parallel_sentence'
+    ) THEN
+        ALTER TABLE examples ADD CONSTRAINT check_example_parallel_sentence CHECK (
+            (parallel_sentence_id IS NULL) = (parallel_language_code IS NULL)
+            AND parallel_language_code <> language_code
+        );
+    END IF;
+END $$;
+
+-- Inflection categories only take the values of the GraphQL enums
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'check_inflection_categories'
+    ) THEN
+        ALTER TABLE inflections ADD CONSTRAINT check_inflection_categories CHECK (
+            (grammatical_case IS NOT NULL OR number IS NOT NULL OR person IS NOT NULL)
+            AND grammatical_case IN ('NOMINATIVE', 'GENITIVE', 'DATIVE', 'ACCUSATIVE', 'INSTRUMENTAL', 'LOCATIVE', 'VOCATIVE')
+            AND number IN ('SINGULAR', 'PLURAL')
+            AND person IN ('FIRST', 'SECOND', 'THIRD')
+        );
+    END IF;
+END $$;
+
+-- A word has one form per combination of categories, missing categories count as equal
+CREATE UNIQUE INDEX IF NOT EXISTS unique_inflection ON inflections (
+    word_id, COALESCE(grammatical_case, ''), COALESCE(number, ''), COALESCE(person, '')
+);
+
+-- Entry kinds only take the values of the GraphQL enum, and an entry has one component per position
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'check_word_kind'
+    ) THEN
+        ALTER TABLE words ADD CONSTRAINT check_word_kind CHECK (kind IN ('WORD', 'PHRASE', 'IDIOM', 'COLLOCATION'));
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'unique_word_component'
+    ) THEN
+        ALTER TABLE word_components ADD CONSTRAINT unique_word_component UNIQUE (word_id, position);
+    END IF;
+END $$;
+
+-- Idioms containing a word are found through its component links, word lists may be filtered by kind
+CREATE INDEX IF NOT EXISTS idx_word_components_component_word_id ON word_components (component_word_id);
+CREATE INDEX IF NOT EXISTS idx_words_kind ON words (kind);
+
+-- Word relations only take the values of the GraphQL enum, never link a word to itself,
+-- and store symmetric relations once, from the word with the lower ID
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'unique_word_relation'
+    ) THEN
+        ALTER TABLE word_relations ADD CONSTRAINT unique_word_relation UNIQUE (word_id, related_word_id, type);
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'check_word_relation'
+    ) THEN
+        ALTER TABLE word_relations ADD CONSTRAINT check_word_relation CHECK (
+            type IN ('SYNONYM', 'ANTONYM', 'HYPERNYM', 'DIMINUTIVE', 'DERIVED_FROM')
+            AND word_id <> related_word_id
+            AND (type NOT IN ('SYNONYM', 'ANTONYM') OR word_id < related_word_id)
+        );
+    END IF;
+END $$;
+
+-- Relations are resolved from both of their words
+CREATE INDEX IF NOT EXISTS idx_word_relations_related_word_id ON word_relations (related_word_id);
+
+-- Lookups of inflected forms resolve them to their word
+CREATE INDEX IF NOT EXISTS idx_inflections_form ON inflections (form);
+CREATE INDEX IF NOT EXISTS idx_inflections_word_id ON inflections (word_id);
+
+-- Word lists filtered by part of speech
+CREATE INDEX IF NOT EXISTS idx_words_part_of_speech ON words (part_of_speech);
+
+-- Translations of a word are listed by rank
+CREATE INDEX IF NOT EXISTS idx_translations_word_id_rank ON translations (word_id, rank);
+
+-- Translations filtered by domain, compared case-insensitively
+CREATE INDEX IF NOT EXISTS idx_translations_domain ON translations (LOWER(domain));
+
+-- Reverse lookups (e.g. English → Polish) go through target_word_id
+CREATE INDEX IF NOT EXISTS idx_translations_target_word_id ON translations (target_word_id);
+
+-- Keyset pagination of the words of one language ordered by term uses (term, id) row comparison
+CREATE INDEX IF NOT EXISTS idx_words_language_term_id ON words (language_code, term, id);
+
+-- Prefix search (LIKE 'abc%') on words, both as typed and with diacritics folded
+CREATE INDEX IF NOT EXISTS idx_words_term_pattern ON words (term text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_words_normalized_word ON words (normalized_word text_pattern_ops);
+
+-- Contains search (LIKE '%abc%') and "did you mean" suggestions are served by trigram indexes
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS idx_words_term_trgm ON words USING gin (term gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_words_normalized_word_trgm ON words USING gin (normalized_word gin_trgm_ops);
@@ -0,0 +1,64 @@
+package lemmatizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Dictionary is a Lemmatizer backed by a morphological dictionary, such as a Morfeusz dump.
+// Unlike RuleBased it only returns real lemmas, but it knows nothing about forms missing from the file.
+type Dictionary struct {
+	lemmas map[string][]string
+}
+
+// NewDictionary reads tab-separated lines of "form<TAB>lemma", any further columns (e.g. tags) are ignored.
+// Empty lines and lines starting with # are skipped. A form listed with several lemmas keeps all of them in file order.
+func NewDictionary(r io.Reader) (*Dictionary, error) {
+	d := &Dictionary{lemmas: make(map[string][]string)}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		columns := strings.Split(text, "\t")
+		if len(columns) < 2 {
+			return nil, fmt.Errorf("line %d: expected form and lemma separated by a tab", line)
+		}
+
+		form := normalize(columns[0])
+		d.lemmas[form] = appendUnique(d.lemmas[form], form, strings.TrimSpace(columns[1]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// LoadDictionary reads a dictionary file in the format accepted by NewDictionary
+func LoadDictionary(path string) (*Dictionary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	d, err := NewDictionary(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return d, nil
+}
+
+// Lemmas returns the lemmas listed for the form
+func (d *Dictionary) Lemmas(form string) []string {
+	return d.lemmas[normalize(form)]
+}
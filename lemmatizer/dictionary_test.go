@@ -0,0 +1,76 @@
+package lemmatizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDictionary = `# form	lemma	tags
+
+psa	pies	subst:sg:gen:m2
+mam	mieć	fin:sg:pri:imperf
+mam	mama	subst:pl:gen:f
+MAM	mieć
+kot	kot	subst:sg:nom:m2
+`
+
+func TestDictionaryLemmas(t *testing.T) {
+	d, err := NewDictionary(strings.NewReader(testDictionary))
+	if err != nil {
+		t.Fatalf("NewDictionary failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		form string
+		want []string
+	}{
+		{name: "irregular form", form: "psa", want: []string{"pies"}},
+		{name: "several lemmas in file order, repeated ones once", form: "mam", want: []string{"mieć", "mama"}},
+		{name: "input is normalized", form: " Mam ", want: []string{"mieć", "mama"}},
+		{name: "headword is not its own lemma", form: "kot", want: nil},
+		// The dictionary does not guess, callers fall back to the inflection tables or to other lemmatizers
+		{name: "unknown form", form: "kotami", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, d.Lemmas(tt.form))
+		})
+	}
+}
+
+func TestDictionaryErrors(t *testing.T) {
+	// Lines are counted with comments and empty lines, so the error points at the right one
+	_, err := NewDictionary(strings.NewReader("# form\tlemma\n\npsa pies\n"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "line 3")
+	}
+
+	_, err = LoadDictionary(filepath.Join(t.TempDir(), "missing.tsv"))
+	assert.Error(t, err)
+}
+
+func TestLoadDictionary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dictionary.tsv")
+	if err := os.WriteFile(path, []byte(testDictionary), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	d, err := LoadDictionary(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"pies"}, d.Lemmas("psa"))
+	}
+
+	// Errors name the file
+	if err := os.WriteFile(path, []byte("psa pies\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	_, err = LoadDictionary(path)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), path)
+	}
+}
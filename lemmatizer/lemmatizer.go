@@ -0,0 +1,29 @@
+// Package lemmatizer guesses the dictionary form (lemma) of inflected Polish words,
+// so lookups can find "kot" when given "kotami".
+package lemmatizer
+
+import "strings"
+
+// Lemmatizer returns candidate lemmas of an inflected form, most likely first.
+// Candidates are only guesses, callers check them against the stored headwords.
+type Lemmatizer interface {
+	Lemmas(form string) []string
+}
+
+// appendUnique adds the candidate unless it is empty, the form itself or already listed
+func appendUnique(candidates []string, form string, candidate string) []string {
+	if candidate == "" || candidate == form {
+		return candidates
+	}
+	for _, c := range candidates {
+		if c == candidate {
+			return candidates
+		}
+	}
+	return append(candidates, candidate)
+}
+
+// normalize prepares user input for matching against endings and dictionary entries
+func normalize(form string) string {
+	return strings.ToLower(strings.TrimSpace(form))
+}
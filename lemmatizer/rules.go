@@ -0,0 +1,117 @@
+package lemmatizer
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// minStemLength keeps short words from being reduced to meaningless stems
+const minStemLength = 2
+
+// rule replaces an inflectional ending with the endings of possible lemmas
+type rule struct {
+	suffix       string
+	replacements []string
+}
+
+// defaultRules cover the regular case endings of nouns and adjectives
+// and the present and past tense endings of verbs.
+// Irregular forms, such as the mobile "e" in "pies" → "psa", need inflection tables or a dictionary.
+var defaultRules = []rule{
+	// Verbs: -ować conjugation
+	{"ujemy", []string{"ować"}},
+	{"ujecie", []string{"ować"}},
+	{"ujesz", []string{"ować"}},
+	{"ują", []string{"ować"}},
+	{"uje", []string{"ować"}},
+	{"uję", []string{"ować"}},
+	// Verbs: -ać conjugation
+	{"acie", []string{"ać"}},
+	{"ają", []string{"ać"}},
+	{"amy", []string{"ać"}},
+	{"asz", []string{"ać"}},
+	{"am", []string{"ać"}},
+	// Verbs: -ić, -yć and -eć conjugations
+	{"imy", []string{"ić"}},
+	{"ymy", []string{"yć"}},
+	{"isz", []string{"ić"}},
+	{"ysz", []string{"yć"}},
+	{"esz", []string{"eć"}},
+	// Verbs: past tense, e.g. "robiłem" → "robić"
+	{"łyśmy", []string{"ć"}},
+	{"liśmy", []string{"ć"}},
+	{"łem", []string{"ć"}},
+	{"łam", []string{"ć"}},
+	{"łeś", []string{"ć"}},
+	{"łaś", []string{"ć"}},
+	{"ła", []string{"ć"}},
+	{"ło", []string{"ć"}},
+	{"li", []string{"ć"}},
+	{"ły", []string{"ć"}},
+	{"ł", []string{"ć"}},
+	// Adjectives
+	{"ego", []string{"y", "i"}},
+	{"emu", []string{"y", "i"}},
+	{"ymi", []string{"y"}},
+	{"imi", []string{"i"}},
+	{"ych", []string{"y"}},
+	{"ich", []string{"i"}},
+	{"ym", []string{"y"}},
+	{"im", []string{"i"}},
+	{"ej", []string{"y", "i"}},
+	// Nouns
+	{"owie", []string{""}},
+	{"ami", []string{"", "a", "o"}},
+	{"ach", []string{"", "a", "o"}},
+	{"owi", []string{""}},
+	{"om", []string{"", "a", "o"}},
+	{"ów", []string{""}},
+	{"em", []string{"", "o", "eć"}},
+	// Single letter endings are the most ambiguous, so they come last
+	{"ą", []string{"a", "y", "ć"}},
+	{"ę", []string{"a", "ć"}},
+	{"a", []string{"", "o", "y"}},
+	{"u", []string{"", "o"}},
+	{"y", []string{"a", ""}},
+	{"e", []string{"y", "i", "o"}},
+	{"i", []string{"ić", "a"}},
+}
+
+// RuleBased is a Lemmatizer stripping common Polish endings.
+// It needs no data files, so it is the default; it guesses, so several candidates may come back.
+type RuleBased struct {
+	rules []rule
+}
+
+// NewRuleBased creates a lemmatizer using the built-in endings
+func NewRuleBased() *RuleBased {
+	rules := make([]rule, len(defaultRules))
+	copy(rules, defaultRules)
+
+	// Longer endings are more specific, so their candidates are tried first
+	sort.SliceStable(rules, func(i, j int) bool {
+		return utf8.RuneCountInString(rules[i].suffix) > utf8.RuneCountInString(rules[j].suffix)
+	})
+	return &RuleBased{rules: rules}
+}
+
+// Lemmas returns the forms obtained by replacing every matching ending, longest endings first
+func (l *RuleBased) Lemmas(form string) []string {
+	form = normalize(form)
+
+	var candidates []string
+	for _, r := range l.rules {
+		if !strings.HasSuffix(form, r.suffix) {
+			continue
+		}
+		stem := strings.TrimSuffix(form, r.suffix)
+		if utf8.RuneCountInString(stem) < minStemLength {
+			continue
+		}
+		for _, replacement := range r.replacements {
+			candidates = appendUnique(candidates, form, stem+replacement)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,75 @@
+package lemmatizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleBasedEveryRule(t *testing.T) {
+	l := NewRuleBased()
+
+	// Every ending gives all of its replacements, whatever other endings also match
+	for _, r := range defaultRules {
+		t.Run(r.suffix, func(t *testing.T) {
+			stem := "prob"
+			candidates := l.Lemmas(stem + r.suffix)
+			for _, replacement := range r.replacements {
+				assert.Contains(t, candidates, stem+replacement)
+			}
+		})
+	}
+}
+
+func TestRuleBasedLemmas(t *testing.T) {
+	l := NewRuleBased()
+
+	tests := []struct {
+		name  string
+		form  string
+		first string
+		want  []string
+	}{
+		{name: "noun instrumental plural", form: "kotami", first: "kot"},
+		{name: "input is normalized", form: " Kotami ", first: "kot"},
+		{name: "ować conjugation", form: "pracujemy", first: "pracować"},
+		{name: "ać conjugation", form: "czytają", first: "czytać"},
+		{name: "ić conjugation", form: "mówimy", first: "mówić"},
+		{name: "past tense", form: "robiłem", first: "robić"},
+		{name: "adjective genitive", form: "nowego", first: "nowy", want: []string{"nowy", "nowi"}},
+		{name: "polish letters in the stem", form: "żółwiami", first: "żółwi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := l.Lemmas(tt.form)
+			if assert.NotEmpty(t, candidates) {
+				// Longer endings are more specific, so their candidates come first
+				assert.Equal(t, tt.first, candidates[0])
+			}
+			for _, want := range tt.want {
+				assert.Contains(t, candidates, want)
+			}
+		})
+	}
+}
+
+func TestRuleBasedNoCandidates(t *testing.T) {
+	l := NewRuleBased()
+
+	tests := []struct {
+		name string
+		form string
+	}{
+		{name: "unknown ending", form: "xyz"},
+		{name: "stem too short", form: "ma"},
+		{name: "empty", form: "   "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Empty(t, l.Lemmas(tt.form))
+		})
+	}
+
+	// Irregular forms need inflection tables or a dictionary
+	assert.NotContains(t, l.Lemmas("psa"), "pies")
+}
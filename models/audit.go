@@ -0,0 +1,57 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Audit holds the audit fields of words, translations and examples, embedded so their columns stay on each table.
+// CreatedBy is the author of the context the row was created with, nil when the author is unknown.
+// DeletedAt makes deletes soft: deleted rows stay in the trash until restored or purged.
+type Audit struct {
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	CreatedBy *string        `gorm:"size:255"`
+}
+
+type authorKey struct{}
+
+// WithAuthor returns a copy of the context whose new words, translations, examples and revisions are attributed to the author
+func WithAuthor(ctx context.Context, author string) context.Context {
+	return context.WithValue(ctx, authorKey{}, author)
+}
+
+// Author returns the author set by WithAuthor, nil when there is none
+func Author(ctx context.Context) *string {
+	if author, ok := ctx.Value(authorKey{}).(string); ok && author != "" {
+		return &author
+	}
+	return nil
+}
+
+// createdBy returns the author of the statement, read from the context it runs with
+func createdBy(tx *gorm.DB) *string {
+	if tx.Statement.Context == nil {
+		return nil
+	}
+	return Author(tx.Statement.Context)
+}
+
+// BeforeCreate attributes a new row to the author of the context, unless it already has one
+func (a *Audit) BeforeCreate(tx *gorm.DB) error {
+	if a.CreatedBy == nil {
+		a.CreatedBy = createdBy(tx)
+	}
+	return nil
+}
+
+// BeforeCreate attributes a new revision to the author of the context
+func (r *Revision) BeforeCreate(tx *gorm.DB) error {
+	if r.CreatedBy == nil {
+		r.CreatedBy = createdBy(tx)
+	}
+	return nil
+}
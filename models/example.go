@@ -1,9 +1,21 @@
 package models
 
-
-// Example represents an example sentence using a translation
+// Example links a translation with a sentence using it.
+// The same sentence may be shared by examples of several translations.
 type Example struct {
-	ID         uint   `gorm:"primaryKey"`
-	TranslationID uint        `gorm:"not null;index"`
-	Sentence      string      `gorm:"unique;not null"`
+	ID            uint     `gorm:"primaryKey"`
+	TranslationID uint     `gorm:"not null;uniqueIndex:unique_sentence"`
+	SentenceID    uint     `gorm:"not null;uniqueIndex:unique_sentence"`
+	Sentence      Sentence `gorm:"foreignKey:SentenceID"`
+	// LanguageCode tags the sentence with one of the two languages of the translation
+	LanguageCode string `gorm:"size:8;not null"`
+	// ParallelSentence is the sentence translated into the other language of the translation, nil when not given
+	ParallelSentenceID   *uint
+	ParallelSentence     *Sentence `gorm:"foreignKey:ParallelSentenceID;constraint:OnDelete:SET NULL"`
+	ParallelLanguageCode *string   `gorm:"size:8"`
+	// Flagged marks an example whose sentence does not use its translation, stored in FLAG validation mode
+	Flagged bool `gorm:"not null;default:false"`
+	Audit
+	// Version counts the changes of the row, starting at 1
+	Version int `gorm:"not null;default:1"`
 }
@@ -0,0 +1,15 @@
+package models
+
+// Inflection is an inflected form of a word, e.g. "psa" for "pies".
+// The grammatical categories are stored as the GraphQL enum names; nil when they do not apply.
+type Inflection struct {
+	ID              uint `gorm:"primaryKey"`
+	WordID          uint `gorm:"not null;index"`
+	Word            Word
+	Form            string  `gorm:"not null;index"`
+	GrammaticalCase *string `gorm:"size:32"`
+	Number          *string `gorm:"size:32"`
+	Person          *string `gorm:"size:32"`
+	// Version counts the changes of the row, starting at 1
+	Version int `gorm:"not null;default:1"`
+}
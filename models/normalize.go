@@ -0,0 +1,37 @@
+package models
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// polishFolder maps Polish letters with diacritics to their plain Latin equivalents
+var polishFolder = strings.NewReplacer(
+	"ą", "a", "ć", "c", "ę", "e", "ł", "l", "ń", "n",
+	"ó", "o", "ś", "s", "ź", "z", "ż", "z",
+)
+
+// FoldPolish lowercases the text and strips Polish diacritics, so "Żółw" becomes "zolw".
+// It must stay in sync with the translate() call used to backfill words.normalized_word in init.sql.
+func FoldPolish(text string) string {
+	return polishFolder.Replace(strings.ToLower(text))
+}
+
+// BeforeSave keeps NormalizedWord in sync with Term, and gives a word without a kind the default one
+func (w *Word) BeforeSave(tx *gorm.DB) error {
+	w.NormalizedWord = FoldPolish(w.Term)
+	if w.Kind == "" {
+		w.Kind = DefaultKind(w.Term)
+	}
+	return nil
+}
+
+// DefaultKind returns the entry kind of a term saved without one: PHRASE for several words, WORD otherwise.
+// It must stay in sync with the backfill of words.kind in init.sql.
+func DefaultKind(term string) string {
+	if len(strings.Fields(term)) > 1 {
+		return "PHRASE"
+	}
+	return "WORD"
+}
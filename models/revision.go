@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Revision is an append-only record of one change of a dictionary entry, made by a mutation.
+// Before and After are JSON snapshots of the changed entity, nil when it did not exist.
+type Revision struct {
+	ID uint `gorm:"primaryKey"`
+	// WordID is the entry the change belongs to: the word itself, or the source word of a translation, example or inflection
+	WordID uint `gorm:"not null;index"`
+	// RelatedWordID is the other word of a word relation, whose history also lists the change; nil for other entities
+	RelatedWordID *uint `gorm:"index"`
+	// EntityType is the RevisionEntity enum name of the changed entity, e.g. TRANSLATION, and EntityID its key
+	EntityType string `gorm:"size:32;not null"`
+	EntityID   uint   `gorm:"not null"`
+	// Action is the RevisionAction enum name, e.g. UPDATE
+	Action string `gorm:"size:16;not null"`
+	// Mutation is the name of the GraphQL mutation which made the change, e.g. updateTranslation
+	Mutation  string  `gorm:"size:64;not null"`
+	Before    *string `gorm:"type:jsonb"`
+	After     *string `gorm:"type:jsonb"`
+	CreatedAt time.Time
+	CreatedBy *string `gorm:"size:255"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// Sentence represents a unique example sentence that can illustrate many translations
+type Sentence struct {
+	ID       uint      `gorm:"primaryKey"`
+	Text     string    `gorm:"unique;not null"`
+	Examples []Example `gorm:"foreignKey:SentenceID;constraint:OnDelete:CASCADE"`
+}
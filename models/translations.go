@@ -1,10 +1,21 @@
 package models
 
-
-// Translation represents an English translation of a Polish word
+// Translation is an edge from a word to a word of another language.
+// The source and target languages are the languages of its two words.
 type Translation struct {
-	ID         uint   `gorm:"primaryKey"`
-	WordID      uint        `gorm:"not null;index"`
-	EnglishWord string      `gorm:"unique;not null"`
-	Examples    []Example   `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
+	ID           uint `gorm:"primaryKey"`
+	WordID       uint `gorm:"not null;uniqueIndex:unique_translation"`
+	Word         Word `gorm:"foreignKey:WordID"`
+	TargetWordID uint `gorm:"not null;uniqueIndex:unique_translation;index"`
+	TargetWord   Word `gorm:"foreignKey:TargetWordID;constraint:OnDelete:CASCADE"`
+	// Rank orders the translations of a word, 1 is its primary meaning
+	Rank int `gorm:"not null"`
+	// Usage labels, nil when not set. Register and Region hold GraphQL enum names, Domain is free text
+	Register *string   `gorm:"size:32"`
+	Domain   *string   `gorm:"size:64"`
+	Region   *string   `gorm:"size:8"`
+	Examples []Example `gorm:"foreignKey:TranslationID;constraint:OnDelete:CASCADE"`
+	Audit
+	// Version counts the changes of the row, starting at 1
+	Version int `gorm:"not null;default:1"`
 }
@@ -1,10 +1,26 @@
 package models
 
-
-// Word represents a unique Polish word
+// Word represents a unique term in one language, e.g. the Polish "zamek" or the English "lock"
 type Word struct {
-	ID         uint   `gorm:"primaryKey"`
-	PolishWord   string         `gorm:"unique;not null"`
-	Translations []Translation  `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE"`
-
+	ID   uint   `gorm:"primaryKey"`
+	Term string `gorm:"not null;uniqueIndex:unique_word_term"`
+	// LanguageCode is the ISO 639-1 code of the term's language, e.g. "pl", "en", "de" or "uk"
+	LanguageCode string `gorm:"size:8;not null;uniqueIndex:unique_word_term"`
+	// NormalizedWord is Term folded by FoldPolish, used for diacritic-insensitive search
+	NormalizedWord string `gorm:"not null;index"`
+	// Grammatical metadata, stored as the GraphQL enum names; nil when unknown
+	PartOfSpeech *string `gorm:"size:32;index"`
+	Gender       *string `gorm:"size:32"`
+	Aspect       *string `gorm:"size:32"`
+	Note         *string
+	// Kind is the entry kind stored as the GraphQL enum name, e.g. WORD or IDIOM
+	Kind string `gorm:"size:16;not null;default:WORD;index"`
+	// Translations leading from this word to terms of other languages
+	Translations []Translation `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE"`
+	Inflections  []Inflection  `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE"`
+	// Components are the words of a multi-word entry, ordered by position
+	Components []WordComponent `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE"`
+	Audit
+	// Version counts the changes of the row, starting at 1
+	Version int `gorm:"not null;default:1"`
 }
@@ -0,0 +1,12 @@
+package models
+
+// WordComponent links a multi-word entry to one of the words it is made of,
+// e.g. "groch" is the second component of "rzucać grochem o ścianę".
+type WordComponent struct {
+	ID              uint `gorm:"primaryKey"`
+	WordID          uint `gorm:"not null;uniqueIndex:unique_word_component"`
+	ComponentWordID uint `gorm:"not null;index"`
+	ComponentWord   Word `gorm:"foreignKey:ComponentWordID;constraint:OnDelete:CASCADE"`
+	// Position orders the components of an entry, starting at 1
+	Position int `gorm:"not null;uniqueIndex:unique_word_component"`
+}
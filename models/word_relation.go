@@ -0,0 +1,12 @@
+package models
+
+// WordRelation is a typed link between two words of the same language, e.g. synonyms.
+// The type is stored as the GraphQL enum name. Symmetric relations are stored once, from the word with the lower ID.
+type WordRelation struct {
+	ID            uint   `gorm:"primaryKey"`
+	WordID        uint   `gorm:"not null;uniqueIndex:unique_word_relation"`
+	Word          Word   `gorm:"foreignKey:WordID;constraint:OnDelete:CASCADE"`
+	RelatedWordID uint   `gorm:"not null;uniqueIndex:unique_word_relation;index"`
+	RelatedWord   Word   `gorm:"foreignKey:RelatedWordID;constraint:OnDelete:CASCADE"`
+	Type          string `gorm:"size:32;not null;uniqueIndex:unique_word_relation"`
+}
@@ -7,11 +7,16 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"translatorapi/apperrors"
 	"translatorapi/graph"
 	generated "translatorapi/graph/generated"
 	"translatorapi/graph/model"
+	"translatorapi/lemmatizer"
 	"translatorapi/mockdatabase"
 	"translatorapi/models"
 
@@ -21,6 +26,7 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/stretchr/testify/assert"
 	"github.com/vektah/gqlparser/v2/ast"
+	"gorm.io/gorm"
 	// "translatorapi/database"
 )
 
@@ -37,14 +43,14 @@ func TestCreate(t *testing.T) {
 	quadResolver := resolver.Query()
 
 	// Wywołujemy funkcję mutacji
-	word, err := mutationResolver.CreateWord(context.TODO(), "a", nil, nil)
+	word, err := mutationResolver.CreateWord(context.TODO(), "a", nil, nil, nil, nil, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("CreateWord nie powiodło się: %v", err)
 	}
 
-	// Sprawdzamy zawartość tabeli "words"
+	// Sprawdzamy zawartość tabeli "words" (tylko polskie słowa, angielskie tłumaczenia też są w niej zapisane)
 	var words []model.Word
-	if err := gormDB.Find(&words).Error; err != nil {
+	if err := gormDB.Where("language_code = ?", "pl").Find(&words).Error; err != nil {
 		t.Fatalf("Nie udało się pobrać danych z tabeli 'words': %v", err)
 	}
 
@@ -55,11 +61,19 @@ func TestCreate(t *testing.T) {
 
 	// Definiujemy oczekiwany wynik
 	expectedWord := model.Word{
-		ID:           "1",
+		ID:           "V29yZDox", // Word:1
+		Term:         "a",
+		LanguageCode: "pl",
 		PolishWord:   "a",
-		Translations: []*model.Translation{}, // Pusta lista tłumaczeń
+		Kind:         model.EntryKindWord,
 	}
 
+	// Znaczniki czasu zależą od chwili utworzenia, więc sprawdzamy tylko, że zostały ustawione
+	assert.False(t, word.CreatedAt.IsZero())
+	assert.Equal(t, word.CreatedAt, word.UpdatedAt)
+	assert.Nil(t, word.CreatedBy)
+	expectedWord.CreatedAt, expectedWord.UpdatedAt = word.CreatedAt, word.UpdatedAt
+
 	// Sprawdzamy, czy zwrócone słowo odpowiada oczekiwanemu
 	assert.Equal(t, &expectedWord, word)
 
@@ -67,46 +81,70 @@ func TestCreate(t *testing.T) {
 	assert.Equal(t, 1, len(words))
 
 	// Tworzymy tłumaczenie
-	translation, err := mutationResolver.CreateTranslation(context.TODO(), "a", "b", nil)
+	a := "a"
+	b := "b"
+	translation, err := mutationResolver.CreateTranslation(context.TODO(), &a, "b", nil, nil)
 	if err != nil {
 		t.Fatalf("CreateTranslation nie powiodło się: %v", err)
 	}
 
 	expectedTranslation := model.Translation{
-		ID:          "1",
-		WordID:      "1",
-		EnglishWord: "b",
-		Examples:    []*model.Example{},
+		ID:             "VHJhbnNsYXRpb246MQ==", // Translation:1
+		WordID:         "V29yZDox",             // Word:1
+		TargetWordID:   "V29yZDoy",             // Word:2, angielskie słowo "b"
+		SourceTerm:     "a",
+		SourceLanguage: "pl",
+		TargetTerm:     "b",
+		TargetLanguage: "en",
+		EnglishWord:    "b",
+		Rank:           1, // jedyne, więc główne znaczenie
 	}
 
+	assert.False(t, translation.CreatedAt.IsZero())
+	expectedTranslation.CreatedAt, expectedTranslation.UpdatedAt = translation.CreatedAt, translation.UpdatedAt
 	assert.Equal(t, &expectedTranslation, translation)
 
-	expectedWord.Translations = append(expectedWord.Translations, &expectedTranslation)
-
 	// Sprawdzamy zawartość tabeli "words"
 	if err := gormDB.Find(&words).Error; err != nil {
 		t.Fatalf("Nie udało się pobrać danych z tabeli 'words': %v", err)
 	}
 
-	currenword, _ := quadResolver.Words(context.Background())
+	currenword, _ := quadResolver.Words(context.Background(), nil)
 
+	// Czas odczytany z bazy ma mniejszą precyzję niż zwrócony przy tworzeniu
+	assert.WithinDuration(t, expectedWord.CreatedAt, currenword[0].CreatedAt, time.Millisecond)
+	expectedWord.CreatedAt, expectedWord.UpdatedAt = currenword[0].CreatedAt, currenword[0].UpdatedAt
 	assert.Equal(t, &expectedWord, currenword[0])
 
+	// Tłumaczenia słowa pochodzą z resolvera pola translations
+	translations, err := resolver.Word().Translations(context.Background(), currenword[0], nil)
+	if err != nil {
+		t.Fatalf("Word.translations nie powiodło się: %v", err)
+	}
+	if assert.Equal(t, 1, len(translations)) {
+		expectedTranslation.CreatedAt, expectedTranslation.UpdatedAt = translations[0].CreatedAt, translations[0].UpdatedAt
+	}
+	assert.Equal(t, []*model.Translation{&expectedTranslation}, translations)
+
 	// Tworzymy przykład
-	example, err := mutationResolver.CreateExample(context.TODO(), "a", "b", "c")
+	example, err := mutationResolver.CreateExample(context.TODO(), &a, &b, "c", nil, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("CreateExample nie powiodło się: %v", err)
 	}
 
 	expectedExample := model.Example{
-		ID:            "1",
-		TranslationID: "1",
+		ID:            "RXhhbXBsZTox",         // Example:1
+		TranslationID: "VHJhbnNsYXRpb246MQ==", // Translation:1
+		SentenceID:    "U2VudGVuY2U6MQ==",     // Sentence:1
 		Sentence:      "c",
+		LanguageCode:  "pl",
 	}
 
+	assert.False(t, example.CreatedAt.IsZero())
+	expectedExample.CreatedAt, expectedExample.UpdatedAt = example.CreatedAt, example.UpdatedAt
 	assert.Equal(t, &expectedExample, example)
 
-	gormDB.Exec("TRUNCATE words, translations, examples RESTART IDENTITY CASCADE;")
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
 
 }
 
@@ -125,11 +163,11 @@ func TestCreateFull(t *testing.T) {
 	b := "b"
 	c := "c"
 
-	mutationResolver.CreateWord(context.TODO(), "a", &b, &c)
+	mutationResolver.CreateWord(context.TODO(), "a", &b, &c, nil, nil, nil, nil, nil)
 
-	// Sprawdzamy zawartość tabeli "words"
+	// Sprawdzamy zawartość tabeli "words" (tylko polskie słowa, angielskie tłumaczenia też są w niej zapisane)
 	var words []model.Word
-	if err := gormDB.Find(&words).Error; err != nil {
+	if err := gormDB.Where("language_code = ?", "pl").Find(&words).Error; err != nil {
 		t.Fatalf("Nie udało się pobrać danych z tabeli 'words': %v", err)
 	}
 	assert.Equal(t, 1, len(words))
@@ -147,52 +185,1886 @@ func TestCreateFull(t *testing.T) {
 	}
 	assert.Equal(t, 1, len(examples))
 
-	_, err = mutationResolver.CreateWord(context.TODO(), "a", nil, nil)
+	_, err = mutationResolver.CreateWord(context.TODO(), "a", nil, nil, nil, nil, nil, nil, nil)
+
+	assert.Error(t, err)
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestDelete(t *testing.T) {
+
+	// Inicjalizujemy bazę danych w pamięci
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	// Tworzymy resolver z wykorzystaniem mockowanej bazy
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+
+	b := "b"
+	c := "c"
+
+	mutationResolver.CreateWord(context.TODO(), "a", &b, &c, nil, nil, nil, nil, nil)
+	a := "a"
+	mutationResolver.DeleteWord(context.TODO(), &a, nil, nil)
+
+	// Sprawdzamy zawartość tabeli "words" (tylko polskie słowa, angielskie tłumaczenia też są w niej zapisane).
+	// Usuwanie jest miękkie, więc modele GORM pomijają usunięte wiersze
+	var words []models.Word
+	if err := gormDB.Where("language_code = ?", "pl").Find(&words).Error; err != nil {
+		t.Fatalf("Nie udało się pobrać danych z tabeli 'words': %v", err)
+	}
+	assert.Equal(t, 0, len(words))
+
+	var translations []models.Translation
+	if err := gormDB.Find(&translations).Error; err != nil {
+		t.Fatalf("Nie udało się pobrać danych z tabeli 'translations': %v", err)
+	}
+	assert.Equal(t, 0, len(translations))
+
+	var examples []models.Example
+	if err := gormDB.Find(&examples).Error; err != nil {
+		t.Fatalf("Nie udało się pobrać danych z tabeli 'examples': %v", err)
+	}
+	assert.Equal(t, 0, len(examples))
+
+	// Usunięte wiersze zostają w koszu
+	var deleted int64
+	if err := gormDB.Unscoped().Model(&models.Example{}).Where("deleted_at IS NOT NULL").Count(&deleted).Error; err != nil {
+		t.Fatalf("Nie udało się policzyć usuniętych przykładów: %v", err)
+	}
+	assert.Equal(t, int64(1), deleted)
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestSharedEnglishTerm(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+
+	lock := "lock"
+
+	// To samo angielskie słowo tłumaczy dwa różne polskie słowa
+	_, err = mutationResolver.CreateWord(context.TODO(), "zamek", &lock, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateWord nie powiodło się: %v", err)
+	}
+	_, err = mutationResolver.CreateWord(context.TODO(), "blokada", &lock, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateWord nie powiodło się: %v", err)
+	}
+
+	var terms []models.Word
+	if err := gormDB.Where("language_code = ?", "en").Find(&terms).Error; err != nil {
+		t.Fatalf("Nie udało się pobrać angielskich słów z tabeli 'words': %v", err)
+	}
+	assert.Equal(t, 1, len(terms))
+
+	var translations []models.Translation
+	if err := gormDB.Find(&translations).Error; err != nil {
+		t.Fatalf("Nie udało się pobrać danych z tabeli 'translations': %v", err)
+	}
+	assert.Equal(t, 2, len(translations))
+
+	// Usunięcie jednego tłumaczenia nie wpływa na drugie słowo
+	zamek := "zamek"
+	_, err = mutationResolver.DeleteTranslation(context.TODO(), &zamek, &lock, nil, nil)
+	assert.NoError(t, err)
+
+	blokada := "blokada"
+	translation, err := mutationResolver.ReplaceTranslation(context.TODO(), &blokada, &lock, "block", nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "block", translation.EnglishWord)
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestSharedSentence(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+
+	zamek := "zamek"
+	lock := "lock"
+	castle := "castle"
+	sentence := "Zamknij drzwi na zamek."
+
+	mutationResolver.CreateWord(context.TODO(), "zamek", &lock, &sentence, nil, nil, nil, nil, nil)
+	_, err = mutationResolver.CreateTranslation(context.TODO(), &zamek, castle, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateTranslation nie powiodło się: %v", err)
+	}
+
+	// To samo zdanie może ilustrować drugie tłumaczenie
+	example, err := mutationResolver.CreateExample(context.TODO(), &zamek, &castle, sentence, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateExample nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "U2VudGVuY2U6MQ==", example.SentenceID) // Sentence:1
+
+	// Ale nie dwa razy to samo tłumaczenie
+	_, err = mutationResolver.CreateExample(context.TODO(), &zamek, &castle, sentence, nil, nil, nil, nil)
+	assert.Error(t, err)
+
+	var sentences []models.Sentence
+	if err := gormDB.Find(&sentences).Error; err != nil {
+		t.Fatalf("Nie udało się pobrać danych z tabeli 'sentences': %v", err)
+	}
+	assert.Equal(t, 1, len(sentences))
+
+	// Usunięcie przykładu z jednego tłumaczenia zostawia drugi
+	_, err = mutationResolver.DeleteExample(context.TODO(), &zamek, &lock, &sentence, nil, nil)
+	assert.NoError(t, err)
+
+	var examples []models.Example
+	if err := gormDB.Find(&examples).Error; err != nil {
+		t.Fatalf("Nie udało się pobrać danych z tabeli 'examples': %v", err)
+	}
+	assert.Equal(t, 1, len(examples))
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestBilingualExamples(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	zamek := "zamek"
+	castle := "castle"
+	mutationResolver.CreateWord(context.TODO(), "zamek", &castle, nil, nil, nil, nil, nil, nil)
+
+	// Zdanie polskie (domyślnie w języku źródłowym) wraz z tłumaczeniem
+	parallel := "The castle stands on a hill."
+	example, err := mutationResolver.CreateExample(context.TODO(), &zamek, &castle, "Zamek stoi na wzgórzu.", nil, nil, &parallel, nil)
+	if err != nil {
+		t.Fatalf("CreateExample nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "pl", example.LanguageCode)
+	if assert.NotNil(t, example.ParallelSentence) && assert.NotNil(t, example.ParallelLanguageCode) {
+		assert.Equal(t, parallel, *example.ParallelSentence)
+		assert.Equal(t, "en", *example.ParallelLanguageCode)
+	}
+
+	// Zdanie angielskie, jego tłumaczenie jest po polsku
+	en := "en"
+	polishParallel := "Zwiedziliśmy stary zamek."
+	example, err = mutationResolver.CreateExample(context.TODO(), &zamek, &castle, "We visited an old castle.", nil, &en, &polishParallel, nil)
+	if err != nil {
+		t.Fatalf("CreateExample nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "en", example.LanguageCode)
+	if assert.NotNil(t, example.ParallelLanguageCode) {
+		assert.Equal(t, "pl", *example.ParallelLanguageCode)
+	}
+
+	// Obie strony są zwracane przez zapytanie examples
+	examples, err := queryResolver.Examples(context.TODO(), "zamek", "castle")
+	if assert.NoError(t, err) && assert.Equal(t, 2, len(examples)) {
+		assert.NotNil(t, examples[0].ParallelSentence)
+		assert.NotNil(t, examples[1].ParallelSentence)
+	}
+
+	// Język spoza tłumaczenia jest odrzucany
+	de := "de"
+	_, err = mutationResolver.CreateExample(context.TODO(), &zamek, &castle, "Das Schloss steht auf einem Hügel.", nil, &de, nil, nil)
+	var appErr *apperrors.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+		assert.Equal(t, "languageCode", appErr.Field)
+	}
+
+	// Pusty parallelSentence usuwa tłumaczenie zdania
+	empty := ""
+	example, err = mutationResolver.UpdateExample(context.TODO(), example.ID, nil, &empty, nil, nil)
+	if assert.NoError(t, err) {
+		assert.Nil(t, example.ParallelSentence)
+		assert.Nil(t, example.ParallelLanguageCode)
+		assert.Equal(t, "We visited an old castle.", example.Sentence)
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestHighlights(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	exampleResolver := resolver.Example()
+
+	kot := "kot"
+	cat := "cat"
+	mutationResolver.CreateWord(context.TODO(), "kot", &cat, nil, nil, nil, nil, nil, nil)
+	accusative := model.GrammaticalCaseAccusative
+	singular := model.GrammaticalNumberSingular
+	mutationResolver.CreateInflection(context.TODO(), &kot, nil, "kota", &accusative, &singular, nil)
+
+	// Przesunięcia są liczone w znakach (code points), a nie w bajtach - "ę" zajmuje jeden znak
+	example, err := mutationResolver.CreateExample(context.TODO(), &kot, &cat, "Widzę Kota, a kot śpi.", nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateExample nie powiodło się: %v", err)
+	}
+	spans, err := exampleResolver.Highlights(context.TODO(), example)
+	if assert.NoError(t, err) && assert.Equal(t, 2, len(spans)) {
+		assert.Equal(t, model.Span{Start: 6, End: 10, Text: "Kota"}, *spans[0])
+		assert.Equal(t, model.Span{Start: 14, End: 17, Text: "kot"}, *spans[1])
+	}
+
+	// W zdaniu angielskim podświetlane jest tłumaczenie
+	en := "en"
+	example, err = mutationResolver.CreateExample(context.TODO(), &kot, &cat, "I have a cat.", nil, &en, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateExample nie powiodło się: %v", err)
+	}
+	spans, err = exampleResolver.Highlights(context.TODO(), example)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(spans)) {
+		assert.Equal(t, model.Span{Start: 9, End: 12, Text: "cat"}, *spans[0])
+	}
+
+	// Fragment słowa nie jest podświetlany
+	example, err = mutationResolver.CreateExample(context.TODO(), &kot, &cat, "Kotlet i katalog.", nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateExample nie powiodło się: %v", err)
+	}
+	spans, err = exampleResolver.Highlights(context.TODO(), example)
+	if assert.NoError(t, err) {
+		assert.Empty(t, spans)
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestExampleValidation(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	kot := "kot"
+	cat := "cat"
+	mutationResolver.CreateWord(context.TODO(), "kot", &cat, nil, nil, nil, nil, nil, nil)
+	accusative := model.GrammaticalCaseAccusative
+	singular := model.GrammaticalNumberSingular
+	mutationResolver.CreateInflection(context.TODO(), &kot, nil, "kota", &accusative, &singular, nil)
+
+	reject := model.ExampleValidationReject
+	flag := model.ExampleValidationFlag
+	off := model.ExampleValidationOff
+
+	// Zdanie bez słowa jest odrzucane w trybie REJECT
+	_, err = mutationResolver.CreateExample(context.TODO(), &kot, &cat, "Pies szczeka.", nil, nil, nil, &reject)
+	var appErr *apperrors.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+		assert.Equal(t, "sentence", appErr.Field)
+	}
+
+	// Odmieniona forma wystarcza
+	example, err := mutationResolver.CreateExample(context.TODO(), &kot, &cat, "Widzę kota.", nil, nil, nil, &reject)
+	if assert.NoError(t, err) {
+		assert.False(t, example.Flagged)
+	}
+
+	// Zdanie angielskie musi zawierać słowo angielskie
+	en := "en"
+	_, err = mutationResolver.CreateExample(context.TODO(), &kot, &cat, "The dog barks.", nil, &en, nil, &reject)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Contains(t, appErr.Error(), "cat")
+	}
+
+	// W trybie FLAG przykład jest zapisywany i oznaczany
+	flagged, err := mutationResolver.CreateExample(context.TODO(), &kot, &cat, "Pies szczeka.", nil, nil, nil, &flag)
+	if assert.NoError(t, err) {
+		assert.True(t, flagged.Flagged)
+	}
+
+	// Tryb ustawiony dla całego serwera może zostać nadpisany w wywołaniu
+	strict := (&graph.Resolver{DB: gormDB, ExampleValidation: model.ExampleValidationReject}).Mutation()
+	_, err = strict.CreateExample(context.TODO(), &kot, &cat, "Ryba pływa.", nil, nil, nil, nil)
+	assert.Error(t, err)
+	_, err = strict.CreateExample(context.TODO(), &kot, &cat, "Ryba pływa.", nil, nil, nil, &off)
+	assert.NoError(t, err)
+
+	// lintExamples zwraca wszystkie naruszenia, również te zapisane bez sprawdzania
+	violations, err := queryResolver.LintExamples(context.TODO(), nil)
+	if assert.NoError(t, err) && assert.Equal(t, 2, len(violations)) {
+		assert.Equal(t, "Pies szczeka.", violations[0].Example.Sentence)
+		assert.Equal(t, "kot", violations[0].ExpectedTerm)
+		assert.Equal(t, "Ryba pływa.", violations[1].Example.Sentence)
+	}
+
+	// Poprawione zdanie przestaje być oznaczone
+	updated, err := mutationResolver.UpdateExample(context.TODO(), flagged.ID, &kot, nil, &flag, nil)
+	if assert.NoError(t, err) {
+		assert.False(t, updated.Flagged)
+	}
+	violations, err = queryResolver.LintExamples(context.TODO(), nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, len(violations))
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestPolishWords(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	lock := "lock"
+	sentence := "Zamknij drzwi na zamek."
+
+	mutationResolver.CreateWord(context.TODO(), "zamek", &lock, &sentence, nil, nil, nil, nil, nil)
+	mutationResolver.CreateWord(context.TODO(), "blokada", &lock, nil, nil, nil, nil, nil, nil)
+
+	// Wyszukiwanie w odwrotnym kierunku: angielski → polski
+	polishWords, err := queryResolver.PolishWords(context.TODO(), "lock")
+	if err != nil {
+		t.Fatalf("PolishWords nie powiodło się: %v", err)
+	}
+	assert.Equal(t, 2, len(polishWords))
+	assert.Equal(t, "zamek", polishWords[0].PolishWord)
+	examples, err := resolver.PolishTranslation().Examples(context.TODO(), polishWords[0])
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(examples)) {
+		assert.Equal(t, sentence, examples[0].Sentence)
+	}
+	assert.Equal(t, "blokada", polishWords[1].PolishWord)
+
+	_, err = queryResolver.PolishWords(context.TODO(), "castle")
+	assert.Error(t, err)
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestWordsConnection(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	for _, w := range []string{"d", "b", "e", "a", "c"} {
+		mutationResolver.CreateWord(context.TODO(), w, nil, nil, nil, nil, nil, nil, nil)
+	}
+
+	orderBy := model.WordOrderFieldPolishWord
+	first := int32(2)
+
+	// Pierwsza strona posortowana po polishWord
+	page, err := queryResolver.WordsConnection(context.TODO(), &first, nil, nil, nil, &orderBy, nil)
+	if err != nil {
+		t.Fatalf("WordsConnection nie powiodło się: %v", err)
+	}
+	assert.Equal(t, 2, len(page.Edges))
+	assert.Equal(t, "a", page.Edges[0].Node.PolishWord)
+	assert.Equal(t, "b", page.Edges[1].Node.PolishWord)
+	assert.True(t, page.PageInfo.HasNextPage)
+	assert.False(t, page.PageInfo.HasPreviousPage)
+
+	// Kolejna strona od kursora
+	page, err = queryResolver.WordsConnection(context.TODO(), &first, page.PageInfo.EndCursor, nil, nil, &orderBy, nil)
+	if err != nil {
+		t.Fatalf("WordsConnection nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "c", page.Edges[0].Node.PolishWord)
+	assert.Equal(t, "d", page.Edges[1].Node.PolishWord)
+
+	// Cofamy się o jedną pozycję
+	last := int32(1)
+	page, err = queryResolver.WordsConnection(context.TODO(), nil, nil, &last, page.PageInfo.StartCursor, &orderBy, nil)
+	if err != nil {
+		t.Fatalf("WordsConnection nie powiodło się: %v", err)
+	}
+	assert.Equal(t, 1, len(page.Edges))
+	assert.Equal(t, "b", page.Edges[0].Node.PolishWord)
+	assert.True(t, page.PageInfo.HasPreviousPage)
+	assert.True(t, page.PageInfo.HasNextPage)
+
+	// Po usunięciu słów przed kursorem nie ma już poprzedniej strony, a po "d" nie ma następnej
+	for _, w := range []string{"a", "b", "e"} {
+		_, err = mutationResolver.DeleteWord(context.TODO(), &w, nil, nil)
+		assert.NoError(t, err)
+	}
+	page, err = queryResolver.WordsConnection(context.TODO(), &first, page.PageInfo.StartCursor, nil, nil, &orderBy, nil)
+	if err != nil {
+		t.Fatalf("WordsConnection nie powiodło się: %v", err)
+	}
+	if assert.Equal(t, 2, len(page.Edges)) {
+		assert.Equal(t, "c", page.Edges[0].Node.PolishWord)
+		assert.Equal(t, "d", page.Edges[1].Node.PolishWord)
+	}
+	assert.False(t, page.PageInfo.HasPreviousPage)
+	assert.False(t, page.PageInfo.HasNextPage)
+
+	_, err = queryResolver.WordsConnection(context.TODO(), &first, nil, &last, nil, nil, nil)
+	assert.Error(t, err)
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestSearchWords(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	for _, w := range []string{"żółw", "zolwik", "żółwica", "kot"} {
+		mutationResolver.CreateWord(context.TODO(), w, nil, nil, nil, nil, nil, nil, nil)
+	}
+
+	// Bez polskich znaków znajdujemy "żółw"
+	words, err := queryResolver.SearchWords(context.TODO(), "zolw", nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("SearchWords nie powiodło się: %v", err)
+	}
+	assert.Equal(t, 1, len(words))
+	assert.Equal(t, "żółw", words[0].PolishWord)
+
+	// Dopasowania bez zwijania znaków są wyżej niż dopasowania po zwinięciu
+	prefix := model.SearchModePrefix
+	words, err = queryResolver.SearchWords(context.TODO(), "zolw", &prefix, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("SearchWords nie powiodło się: %v", err)
+	}
+	assert.Equal(t, 3, len(words))
+	assert.Equal(t, "zolwik", words[0].PolishWord)
+	assert.Equal(t, "żółw", words[1].PolishWord)
+
+	// Bez zwijania znaków "zolw" pasuje tylko do "zolwik"
+	fold := false
+	words, err = queryResolver.SearchWords(context.TODO(), "zolw", &prefix, &fold, nil, nil)
+	if err != nil {
+		t.Fatalf("SearchWords nie powiodło się: %v", err)
+	}
+	assert.Equal(t, 1, len(words))
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestSuggestions(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	castle := "castle"
+	mutationResolver.CreateWord(context.TODO(), "zamek", &castle, nil, nil, nil, nil, nil, nil)
+	mutationResolver.CreateWord(context.TODO(), "żółwik", nil, nil, nil, nil, nil, nil, nil)
+
+	suggestions, err := queryResolver.Suggest(context.TODO(), "zamke", nil, nil)
+	if err != nil {
+		t.Fatalf("Suggest nie powiodło się: %v", err)
+	}
+	assert.NotEmpty(t, suggestions)
+	assert.Equal(t, "zamek", suggestions[0].Word)
+	assert.Equal(t, "pl", suggestions[0].LanguageCode)
+
+	// Błąd "word not found" podpowiada najbliższe słowa
+	_, err = queryResolver.Translations(context.TODO(), "zolwik", nil, nil, nil)
+	var appErr *apperrors.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.NotFound, appErr.Code)
+		assert.Equal(t, []string{"żółwik"}, appErr.Extensions["suggestions"])
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestUpdate(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+
+	b := "b"
+	c := "c"
+	zolw := "żółw"
+	kot := "kot"
+	turtle := "turtle"
+
+	mutationResolver.CreateWord(context.TODO(), "zolw", &b, &c, nil, nil, nil, nil, nil)
+	mutationResolver.CreateWord(context.TODO(), "kot", nil, nil, nil, nil, nil, nil, nil)
+
+	// Poprawiamy literówkę, tłumaczenia i przykłady zostają
+	word, err := mutationResolver.UpdateWord(context.TODO(), "V29yZDox", &zolw, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateWord nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "żółw", word.PolishWord)
+	translations, err := resolver.Word().Translations(context.TODO(), word, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(translations)) {
+		examples, err := resolver.Translation().Examples(context.TODO(), translations[0])
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(examples))
+	}
+
+	translation, err := mutationResolver.UpdateTranslation(context.TODO(), "VHJhbnNsYXRpb246MQ==", &turtle, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateTranslation nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "turtle", translation.EnglishWord)
+	examples, err := resolver.Translation().Examples(context.TODO(), translation)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(examples))
+
+	slowly := "Żółw idzie powoli."
+	example, err := mutationResolver.UpdateExample(context.TODO(), "RXhhbXBsZTox", &slowly, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateExample nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "Żółw idzie powoli.", example.Sentence)
+
+	// Konflikt z istniejącym słowem
+	_, err = mutationResolver.UpdateWord(context.TODO(), "V29yZDox", &kot, nil, nil, nil, nil, nil, nil, nil)
+	var appErr *apperrors.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.AlreadyExists, appErr.Code)
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestGrammar(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	noun := model.PartOfSpeechNoun
+	verb := model.PartOfSpeechVerb
+	feminine := model.GenderFeminine
+	perfective := model.AspectPerfective
+	note := "potoczne"
+
+	kawa, err := mutationResolver.CreateWord(context.TODO(), "kawa", nil, nil, &noun, &feminine, nil, &note, nil)
+	if err != nil {
+		t.Fatalf("CreateWord nie powiodło się: %v", err)
+	}
+	assert.Equal(t, &noun, kawa.PartOfSpeech)
+	assert.Equal(t, &feminine, kawa.Gender)
+	assert.Nil(t, kawa.Aspect)
+	assert.Equal(t, &note, kawa.Note)
+
+	_, err = mutationResolver.CreateWord(context.TODO(), "zrobić", nil, nil, &verb, nil, &perfective, nil, nil)
+	assert.NoError(t, err)
+
+	// Rodzaj ma sens tylko dla rzeczowników
+	_, err = mutationResolver.CreateWord(context.TODO(), "robić", nil, nil, &verb, &feminine, nil, nil, nil)
+	var appErr *apperrors.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+		assert.Equal(t, "gender", appErr.Field)
+	}
+
+	// Filtrowanie list słów
+	words, err := queryResolver.Words(context.TODO(), &model.WordFilter{PartOfSpeech: &noun})
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(words)) {
+		assert.Equal(t, "kawa", words[0].PolishWord)
+	}
+	words, err = queryResolver.SearchWords(context.TODO(), "zrobic", nil, nil, nil, &model.WordFilter{Aspect: &perfective})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, len(words))
+	}
+
+	// Zmiana części mowy usuwa rodzaj, pusta notatka ją czyści
+	empty := ""
+	kawa, err = mutationResolver.UpdateWord(context.TODO(), kawa.ID, nil, nil, &verb, nil, nil, &empty, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateWord nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "kawa", kawa.PolishWord)
+	assert.Equal(t, &verb, kawa.PartOfSpeech)
+	assert.Nil(t, kawa.Gender)
+	assert.Nil(t, kawa.Note)
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestInflections(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	dog := "dog"
+	pies := "pies"
+	genitive := model.GrammaticalCaseGenitive
+	singular := model.GrammaticalNumberSingular
+
+	mutationResolver.CreateWord(context.TODO(), "pies", &dog, nil, nil, nil, nil, nil, nil)
+
+	inflection, err := mutationResolver.CreateInflection(context.TODO(), &pies, nil, "psa", &genitive, &singular, nil)
+	if err != nil {
+		t.Fatalf("CreateInflection nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "psa", inflection.Form)
+	assert.Equal(t, &genitive, inflection.GrammaticalCase)
+
+	// Ta sama komórka tabeli odmiany nie może mieć dwóch form
+	_, err = mutationResolver.CreateInflection(context.TODO(), &pies, nil, "psu", &genitive, &singular, nil)
+	var appErr *apperrors.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.AlreadyExists, appErr.Code)
+	}
+
+	// Forma bez kategorii gramatycznych jest odrzucana
+	_, err = mutationResolver.CreateInflection(context.TODO(), &pies, nil, "psem", nil, nil, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+
+	// Forma odmieniona prowadzi do tłumaczeń słowa podstawowego
+	translations, err := queryResolver.Translations(context.TODO(), "psa", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Translations nie powiodło się: %v", err)
+	}
+	if assert.Equal(t, 1, len(translations)) {
+		assert.Equal(t, "dog", translations[0].EnglishWord)
+		assert.Equal(t, model.FormMatchSourceInflection, translations[0].MatchedForm.Source)
+		assert.Equal(t, "psa", translations[0].MatchedForm.Form)
+		assert.Equal(t, inflection.ID, translations[0].MatchedForm.Inflection.ID)
+	}
+
+	translations, err = queryResolver.Translations(context.TODO(), "pies", nil, nil, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(translations)) {
+		assert.Equal(t, model.FormMatchSourceHeadword, translations[0].MatchedForm.Source)
+		assert.Nil(t, translations[0].MatchedForm.Inflection)
+	}
+
+	// Zmiana i usunięcie formy
+	dative := model.GrammaticalCaseDative
+	psu := "psu"
+	inflection, err = mutationResolver.UpdateInflection(context.TODO(), inflection.ID, &psu, &dative, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateInflection nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "psu", inflection.Form)
+	assert.Equal(t, &singular, inflection.Number)
+
+	word, err := queryResolver.Node(context.TODO(), inflection.WordID)
+	if assert.NoError(t, err) {
+		inflections, err := resolver.Word().Inflections(context.TODO(), word.(*model.Word))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(inflections))
+	}
+
+	_, err = mutationResolver.DeleteInflection(context.TODO(), inflection.ID, nil)
+	assert.NoError(t, err)
+	_, err = queryResolver.Translations(context.TODO(), "psu", nil, nil, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.NotFound, appErr.Code)
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestLemmatizer(t *testing.T) {
+
+	// Reguły zgadują formę podstawową po końcówce
+	rules := lemmatizer.NewRuleBased()
+	assert.Contains(t, rules.Lemmas("kotami"), "kot")
+	assert.Contains(t, rules.Lemmas("robiłem"), "robić")
+	assert.Contains(t, rules.Lemmas("pracujesz"), "pracować")
+	assert.Contains(t, rules.Lemmas("dobrego"), "dobry")
+	assert.Empty(t, rules.Lemmas("ma"))
+
+	// Słownik zna formy nieregularne
+	dictionary, err := lemmatizer.NewDictionary(strings.NewReader("# forma\tlemat\npsa\tpies\tsubst:sg:gen:m2\n"))
+	if err != nil {
+		t.Fatalf("NewDictionary nie powiodło się: %v", err)
+	}
+	assert.Equal(t, []string{"pies"}, dictionary.Lemmas("Psa"))
+	_, err = lemmatizer.NewDictionary(strings.NewReader("psa pies\n"))
+	assert.Error(t, err)
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	cat := "cat"
+	dog := "dog"
+	mutationResolver.CreateWord(context.TODO(), "kot", &cat, nil, nil, nil, nil, nil, nil)
+	mutationResolver.CreateWord(context.TODO(), "pies", &dog, nil, nil, nil, nil, nil, nil)
+
+	// Bez tabeli odmiany tłumaczenia znajduje lematyzator
+	translations, err := queryResolver.Translations(context.TODO(), "kotami", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Translations nie powiodło się: %v", err)
+	}
+	if assert.Equal(t, 1, len(translations)) {
+		assert.Equal(t, "cat", translations[0].EnglishWord)
+		assert.Equal(t, model.FormMatchSourceLemmatizer, translations[0].MatchedForm.Source)
+	}
+
+	words, err := queryResolver.SearchWords(context.TODO(), "kotami", nil, nil, nil, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(words)) {
+		assert.Equal(t, "kot", words[0].PolishWord)
+	}
+
+	// Reguły nie radzą sobie z "psa", słownik tak
+	_, err = queryResolver.Translations(context.TODO(), "psa", nil, nil, nil)
+	assert.Error(t, err)
+
+	resolver.Lemmatizer = dictionary
+	translations, err = resolver.Query().Translations(context.TODO(), "psa", nil, nil, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(translations)) {
+		assert.Equal(t, "dog", translations[0].EnglishWord)
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestLanguages(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	// Słowo niemieckie tłumaczone na ukraiński i angielski
+	schloss, err := mutationResolver.CreateTerm(context.TODO(), "Schloss", "de", nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateTerm nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "de", schloss.LanguageCode)
+
+	translation, err := mutationResolver.AddTranslation(context.TODO(), &schloss.ID, nil, nil, "замок", "uk", nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("AddTranslation nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "Schloss", translation.SourceTerm)
+	assert.Equal(t, "de", translation.SourceLanguage)
+	assert.Equal(t, "замок", translation.TargetTerm)
+	assert.Equal(t, "uk", translation.TargetLanguage)
+
+	sourceTerm, de := "Schloss", "de"
+	_, err = mutationResolver.AddTranslation(context.TODO(), nil, &sourceTerm, &de, "castle", "en", nil, nil, nil, nil)
+	assert.NoError(t, err)
+
+	// To samo angielskie słowo tłumaczy też polski "zamek"
+	castle := "castle"
+	_, err = mutationResolver.CreateWord(context.TODO(), "zamek", &castle, nil, nil, nil, nil, nil, nil)
+	assert.NoError(t, err)
+
+	translations, err := queryResolver.Lookup(context.TODO(), "Schloss", "de", nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(translations))
+
+	uk := "uk"
+	translations, err = queryResolver.Lookup(context.TODO(), "Schloss", "de", &uk, nil, nil, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(translations)) {
+		assert.Equal(t, "замок", translations[0].TargetTerm)
+	}
+
+	// Odwrotny kierunek: kto tłumaczy się na "castle"
+	translations, err = queryResolver.ReverseLookup(context.TODO(), "castle", "en", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(translations))
+
+	// Zapytania polsko-angielskie widzą tylko polskie słowa
+	polishWords, err := queryResolver.PolishWords(context.TODO(), "castle")
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(polishWords)) {
+		assert.Equal(t, "zamek", polishWords[0].PolishWord)
+	}
+	words, err := queryResolver.Words(context.TODO(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(words))
+	words, err = queryResolver.Words(context.TODO(), &model.WordFilter{LanguageCode: &de})
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(words)) {
+		assert.Equal(t, "Schloss", words[0].Term)
+	}
+
+	// Błędne kody języków i tłumaczenie na własny język
+	var appErr *apperrors.Error
+	_, err = mutationResolver.CreateTerm(context.TODO(), "Burg", "German", nil, nil, nil, nil, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+	_, err = mutationResolver.AddTranslation(context.TODO(), &schloss.ID, nil, nil, "Burg", "de", nil, nil, nil, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+
+	// Ten sam zapis w innym języku to inne słowo
+	_, err = mutationResolver.CreateTerm(context.TODO(), "zamek", "pl", nil, nil, nil, nil, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.AlreadyExists, appErr.Code)
+	}
+	_, err = mutationResolver.CreateTerm(context.TODO(), "castle", "de", nil, nil, nil, nil, nil)
+	assert.NoError(t, err)
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestTranslationLabels(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	castle := "castle"
+	mutationResolver.CreateWord(context.TODO(), "zamek", &castle, nil, nil, nil, nil, nil, nil)
+
+	// "zamek" w znaczeniu błyskawicznym: "zip" w Wielkiej Brytanii, "zipper" w USA
+	zamek := "zamek"
+	pl := "pl"
+	clothing := "clothing"
+	uk := model.RegionUk
+	us := model.RegionUs
+	zip, err := mutationResolver.AddTranslation(context.TODO(), nil, &zamek, &pl, "zip", "en", nil, nil, &clothing, &uk)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "clothing", *zip.Domain)
+		assert.Equal(t, model.RegionUk, *zip.Region)
+		assert.Nil(t, zip.Register)
+	}
+	_, err = mutationResolver.AddTranslation(context.TODO(), nil, &zamek, &pl, "zipper", "en", nil, nil, &clothing, &us)
+	assert.NoError(t, err)
+
+	// Dziedzina jest porównywana bez względu na wielkość liter
+	upperClothing := "CLOTHING"
+	translations, err := queryResolver.Translations(context.TODO(), "zamek", nil, &upperClothing, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, len(translations))
+	}
+	translations, err = queryResolver.Translations(context.TODO(), "zamek", nil, nil, &us)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(translations)) {
+		assert.Equal(t, "zipper", translations[0].TargetTerm)
+	}
+
+	// Same etykiety można zmienić bez zmiany słowa docelowego
+	architecture := " architecture "
+	formal := model.RegisterFormal
+	translation, err := mutationResolver.UpdateTranslation(context.TODO(), "VHJhbnNsYXRpb246MQ==", nil, nil, &formal, &architecture, nil, nil, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "castle", translation.TargetTerm)
+		assert.Equal(t, "architecture", *translation.Domain)
+		assert.Equal(t, model.RegisterFormal, *translation.Register)
+	}
+	translations, err = queryResolver.Lookup(context.TODO(), "zamek", "pl", nil, &formal, nil, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(translations)) {
+		assert.Equal(t, "castle", translations[0].TargetTerm)
+	}
+
+	// Pusta dziedzina ją usuwa, clearLabels usuwa wszystkie etykiety
+	empty := ""
+	translation, err = mutationResolver.UpdateTranslation(context.TODO(), "VHJhbnNsYXRpb246MQ==", nil, nil, nil, &empty, nil, nil, nil)
+	if assert.NoError(t, err) {
+		assert.Nil(t, translation.Domain)
+		assert.NotNil(t, translation.Register)
+	}
+	clearAll := true
+	translation, err = mutationResolver.UpdateTranslation(context.TODO(), zip.ID, nil, nil, nil, nil, nil, &clearAll, nil)
+	if assert.NoError(t, err) {
+		assert.Nil(t, translation.Domain)
+		assert.Nil(t, translation.Region)
+	}
+
+	// Zbyt długa dziedzina jest odrzucana
+	long := strings.Repeat("x", 65)
+	_, err = mutationResolver.UpdateTranslation(context.TODO(), zip.ID, nil, nil, nil, &long, nil, nil, nil)
+	var appErr *apperrors.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+		assert.Equal(t, "domain", appErr.Field)
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestTranslationRanks(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	zamek := "zamek"
+	castle := "castle"
+	mutationResolver.CreateWord(context.TODO(), "zamek", &castle, nil, nil, nil, nil, nil, nil)
+	mutationResolver.CreateTranslation(context.TODO(), &zamek, "lock", nil, nil)
+	mutationResolver.CreateTranslation(context.TODO(), &zamek, "zipper", nil, nil)
+
+	// targetTerms zwraca słowa docelowe w kolejności zwróconej przez zapytanie
+	targetTerms := func(translations []*model.Translation) []string {
+		terms := make([]string, 0, len(translations))
+		for _, translation := range translations {
+			terms = append(terms, translation.TargetTerm)
+		}
+		return terms
+	}
+
+	// Nowe tłumaczenia trafiają na koniec
+	translations, err := queryResolver.Translations(context.TODO(), "zamek", nil, nil, nil)
+	if assert.NoError(t, err) && assert.Equal(t, []string{"castle", "lock", "zipper"}, targetTerms(translations)) {
+		assert.Equal(t, int32(3), translations[2].Rank)
+	}
+
+	// Niewymienione tłumaczenia zachowują kolejność za wymienionymi
+	translations, err = mutationResolver.ReorderTranslations(context.TODO(), "zamek", []string{"lock", "castle"}, nil)
+	if assert.NoError(t, err) && assert.Equal(t, []string{"lock", "castle", "zipper"}, targetTerms(translations)) {
+		assert.Equal(t, int32(1), translations[0].Rank)
+		assert.Equal(t, int32(2), translations[1].Rank)
+		assert.Equal(t, int32(3), translations[2].Rank)
+	}
+	translations, err = queryResolver.Translations(context.TODO(), "zamek", nil, nil, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"lock", "castle", "zipper"}, targetTerms(translations))
+	}
+	words, _ := queryResolver.Words(context.TODO(), nil)
+	translations, err = resolver.Word().Translations(context.TODO(), words[0], nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"lock", "castle", "zipper"}, targetTerms(translations))
+	}
+
+	// Nowe słowo docelowe zajmuje miejsce zastąpionego
+	_, err = mutationResolver.ReplaceTranslation(context.TODO(), &zamek, &castle, "fortress", nil, nil, nil)
+	assert.NoError(t, err)
+	translations, err = queryResolver.Translations(context.TODO(), "zamek", nil, nil, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"lock", "fortress", "zipper"}, targetTerms(translations))
+	}
+
+	// Nieznane i powtórzone tłumaczenia są odrzucane
+	var appErr *apperrors.Error
+	_, err = mutationResolver.ReorderTranslations(context.TODO(), "zamek", []string{"door"}, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.NotFound, appErr.Code)
+		assert.Equal(t, "englishWords", appErr.Field)
+	}
+	_, err = mutationResolver.ReorderTranslations(context.TODO(), "zamek", []string{"lock", "lock"}, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestWordRelations(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	wordResolver := resolver.Word()
+
+	duzy, _ := mutationResolver.CreateWord(context.TODO(), "duży", nil, nil, nil, nil, nil, nil, nil)
+	wielki, _ := mutationResolver.CreateWord(context.TODO(), "wielki", nil, nil, nil, nil, nil, nil, nil)
+	mutationResolver.CreateWord(context.TODO(), "mały", nil, nil, nil, nil, nil, nil, nil)
+	mutationResolver.CreateWord(context.TODO(), "pies", nil, nil, nil, nil, nil, nil, nil)
+	zwierze, _ := mutationResolver.CreateWord(context.TODO(), "zwierzę", nil, nil, nil, nil, nil, nil, nil)
+
+	duzyTerm := "duży"
+	wielkiTerm := "wielki"
+	malyTerm := "mały"
+	piesTerm := "pies"
+	zwierzeTerm := "zwierzę"
+
+	relation, err := mutationResolver.LinkWords(context.TODO(), nil, &duzyTerm, nil, &wielkiTerm, model.RelationTypeSynonym)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "wielki", relation.Word.Term)
+		assert.False(t, relation.Inverse)
+	}
+	_, err = mutationResolver.LinkWords(context.TODO(), nil, &duzyTerm, nil, &malyTerm, model.RelationTypeAntonym)
+	assert.NoError(t, err)
+	_, err = mutationResolver.LinkWords(context.TODO(), nil, &piesTerm, nil, &zwierzeTerm, model.RelationTypeHypernym)
+	assert.NoError(t, err)
+
+	// Synonimia działa w obie strony, więc odwrotne powiązanie już istnieje
+	var appErr *apperrors.Error
+	_, err = mutationResolver.LinkWords(context.TODO(), nil, &wielkiTerm, nil, &duzyTerm, model.RelationTypeSynonym)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.AlreadyExists, appErr.Code)
+	}
+	// Odwrotna relacja niesymetryczna byłaby sprzeczna
+	_, err = mutationResolver.LinkWords(context.TODO(), nil, &zwierzeTerm, nil, &piesTerm, model.RelationTypeHypernym)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+	_, err = mutationResolver.LinkWords(context.TODO(), nil, &duzyTerm, nil, &duzyTerm, model.RelationTypeSynonym)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+	// Słowa różnych języków łączą tłumaczenia, a nie relacje
+	big, _ := mutationResolver.CreateTerm(context.TODO(), "big", "en", nil, nil, nil, nil, nil)
+	_, err = mutationResolver.LinkWords(context.TODO(), nil, &duzyTerm, &big.ID, nil, model.RelationTypeSynonym)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+		assert.Equal(t, "relatedWordId", appErr.Field)
+	}
+
+	// Relacje są widoczne z obu słów
+	synonym := model.RelationTypeSynonym
+	related, err := wordResolver.Related(context.TODO(), wielki, &synonym)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(related)) {
+		assert.Equal(t, "duży", related[0].Word.Term)
+		assert.False(t, related[0].Inverse)
+	}
+	related, err = wordResolver.Related(context.TODO(), duzy, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, len(related))
+	}
+	related, err = wordResolver.Related(context.TODO(), zwierze, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(related)) {
+		assert.Equal(t, "pies", related[0].Word.Term)
+		assert.Equal(t, model.RelationTypeHypernym, related[0].Type)
+		assert.True(t, related[0].Inverse)
+	}
+
+	// Usunięcie relacji symetrycznej działa z obu stron
+	removed, err := mutationResolver.UnlinkWords(context.TODO(), nil, &wielkiTerm, nil, &duzyTerm, model.RelationTypeSynonym)
+	assert.NoError(t, err)
+	assert.True(t, removed)
+	related, err = wordResolver.Related(context.TODO(), duzy, &synonym)
+	if assert.NoError(t, err) {
+		assert.Empty(t, related)
+	}
+	_, err = mutationResolver.UnlinkWords(context.TODO(), nil, &wielkiTerm, nil, &duzyTerm, model.RelationTypeSynonym)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.NotFound, appErr.Code)
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples, word_relations RESTART IDENTITY CASCADE;")
+
+}
+
+func TestIdioms(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	for _, w := range []string{"rzucać", "groch", "o", "ściana", "kawa", "mocny"} {
+		mutationResolver.CreateWord(context.TODO(), w, nil, nil, nil, nil, nil, nil, nil)
+	}
+
+	// Termin z kilkoma słowami jest domyślnie frazą
+	phrase, err := mutationResolver.CreateWord(context.TODO(), "mocna kawa", nil, nil, nil, nil, nil, nil, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, model.EntryKindPhrase, phrase.Kind)
+	}
+	idiom := model.EntryKindIdiom
+	peas := "rzucać grochem o ścianę"
+	entry, err := mutationResolver.CreateWord(context.TODO(), peas, nil, nil, nil, nil, nil, nil, &idiom)
+	if assert.NoError(t, err) {
+		assert.Equal(t, model.EntryKindIdiom, entry.Kind)
+	}
+
+	entry, err = mutationResolver.SetComponents(context.TODO(), nil, &peas, []string{"rzucać", "groch", "o", "ściana"}, nil)
+	if err != nil {
+		t.Fatalf("SetComponents nie powiodło się: %v", err)
+	}
+	components, err := resolver.Word().Components(context.TODO(), entry)
+	if assert.NoError(t, err) && assert.Equal(t, 4, len(components)) {
+		assert.Equal(t, "rzucać", components[0].Term)
+		assert.Equal(t, "ściana", components[3].Term)
+	}
+
+	// Kolokacja z tym samym słowem nie jest idiomem
+	collocation := model.EntryKindCollocation
+	mocnaKawa := "mocna kawa"
+	_, err = mutationResolver.UpdateWord(context.TODO(), phrase.ID, nil, nil, nil, nil, nil, nil, &collocation, nil)
+	assert.NoError(t, err)
+	_, err = mutationResolver.SetComponents(context.TODO(), nil, &mocnaKawa, []string{"mocny", "kawa"}, nil)
+	assert.NoError(t, err)
+
+	// Odmieniona forma "grochem" jest sprowadzana do słowa "groch" przez lematyzator
+	idioms, err := queryResolver.IdiomsContaining(context.TODO(), "grochem", nil, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(idioms)) {
+		assert.Equal(t, peas, idioms[0].Term)
+	}
+	idioms, err = queryResolver.IdiomsContaining(context.TODO(), "kawa", nil, nil)
+	if assert.NoError(t, err) {
+		assert.Empty(t, idioms)
+	}
+	idioms, err = queryResolver.IdiomsContaining(context.TODO(), "kawa", nil, &collocation)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, len(idioms))
+	}
+
+	// Listy słów można zawęzić do rodzaju hasła
+	words, err := queryResolver.Words(context.TODO(), &model.WordFilter{Kind: &idiom})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, len(words))
+	}
+
+	// Pojedyncze słowo nie ma składników, a brakujący składnik jest zgłaszany
+	var appErr *apperrors.Error
+	groch := "groch"
+	_, err = mutationResolver.SetComponents(context.TODO(), nil, &groch, []string{"o"}, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+	_, err = mutationResolver.SetComponents(context.TODO(), nil, &peas, []string{"rzucać", "fasola"}, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.NotFound, appErr.Code)
+		assert.Equal(t, "components", appErr.Field)
+	}
+	wordKind := model.EntryKindWord
+	_, err = mutationResolver.UpdateWord(context.TODO(), entry.ID, nil, nil, nil, nil, nil, nil, &wordKind, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, "kind", appErr.Field)
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples, word_components RESTART IDENTITY CASCADE;")
+
+}
+
+func TestTrash(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+	var appErr *apperrors.Error
+
+	// Słowa, tłumaczenia i przykłady są przypisywane autorowi z kontekstu
+	ctx := models.WithAuthor(context.TODO(), "anna")
+	zamek := "zamek"
+	lock := "lock"
+	castle := "castle"
+	sentence := "Zamknij drzwi na zamek."
+
+	word, err := mutationResolver.CreateWord(ctx, zamek, &lock, &sentence, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateWord nie powiodło się: %v", err)
+	}
+	if assert.NotNil(t, word.CreatedBy) {
+		assert.Equal(t, "anna", *word.CreatedBy)
+	}
+	castleTranslation, err := mutationResolver.CreateTranslation(ctx, &zamek, castle, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateTranslation nie powiodło się: %v", err)
+	}
+	if assert.NotNil(t, castleTranslation.CreatedBy) {
+		assert.Equal(t, "anna", *castleTranslation.CreatedBy)
+	}
+
+	// Zmiana odświeża updatedAt, ale nie createdAt
+	domain := "architecture"
+	updated, err := mutationResolver.UpdateTranslation(ctx, castleTranslation.ID, nil, nil, nil, &domain, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateTranslation nie powiodło się: %v", err)
+	}
+	assert.True(t, updated.UpdatedAt.After(updated.CreatedAt))
+
+	// Usunięte tłumaczenie trafia do kosza razem ze swoim przykładem
+	_, err = mutationResolver.DeleteTranslation(ctx, &zamek, &lock, nil, nil)
+	assert.NoError(t, err)
+
+	translations, err := queryResolver.Translations(ctx, zamek, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(translations))
+
+	trash, err := queryResolver.Trash(ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("Trash nie powiodło się: %v", err)
+	}
+	if !assert.Equal(t, 2, len(trash)) {
+		t.FailNow()
+	}
+	// Usunięte razem: najpierw tłumaczenie, potem jego przykład
+	assert.Equal(t, trash[0].DeletedAt, trash[1].DeletedAt)
+	lockTranslation, ok := trash[0].Node.(*model.Translation)
+	if !assert.True(t, ok) {
+		t.FailNow()
+	}
+	assert.Equal(t, "lock", lockTranslation.TargetTerm)
+	example, ok := trash[1].Node.(*model.Example)
+	if !assert.True(t, ok) {
+		t.FailNow()
+	}
+	assert.Equal(t, sentence, example.Sentence)
+
+	// Przykład nie wraca bez swojego tłumaczenia
+	_, err = mutationResolver.RestoreExample(ctx, example.ID, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+
+	// Przywrócenie tłumaczenia przywraca też jego przykład
+	restored, err := mutationResolver.RestoreTranslation(ctx, lockTranslation.ID, nil)
+	if err != nil {
+		t.Fatalf("RestoreTranslation nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "lock", restored.TargetTerm)
+	examples, err := queryResolver.Examples(ctx, zamek, lock)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(examples))
+
+	// Przywrócić można tylko usunięte
+	_, err = mutationResolver.RestoreTranslation(ctx, lockTranslation.ID, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.NotFound, appErr.Code)
+	}
+
+	genitive := model.GrammaticalCaseGenitive
+	inflection, err := mutationResolver.CreateInflection(ctx, nil, &word.ID, "zamku", &genitive, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateInflection nie powiodło się: %v", err)
+	}
+
+	// Usunięcie słowa usuwa jego tłumaczenia i ich przykłady
+	_, err = mutationResolver.DeleteWord(ctx, nil, &word.ID, nil)
+	assert.NoError(t, err)
+	_, err = queryResolver.Node(ctx, word.ID)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.NotFound, appErr.Code)
+	}
+	trash, err = queryResolver.Trash(ctx, nil, nil)
+	assert.NoError(t, err)
+	if assert.Equal(t, 4, len(trash)) {
+		assert.IsType(t, &model.Word{}, trash[0].Node)
+		assert.IsType(t, &model.Example{}, trash[3].Node)
+	}
+
+	// Odmiany usuniętego słowa są ukryte do jego przywrócenia
+	_, err = queryResolver.Node(ctx, inflection.ID)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.NotFound, appErr.Code)
+	}
+	_, err = mutationResolver.DeleteInflection(ctx, inflection.ID, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.NotFound, appErr.Code)
+	}
+	if deletedWord, ok := trash[0].Node.(*model.Word); ok {
+		inflections, err := resolver.Word().Inflections(ctx, deletedWord)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(inflections))
+	}
+
+	// Tłumaczenie nie wraca bez swojego słowa
+	_, err = mutationResolver.RestoreTranslation(ctx, lockTranslation.ID, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+
+	// Usunięte słowo nie blokuje nowego o tej samej pisowni, ale nowe blokuje przywrócenie starego
+	again, err := mutationResolver.CreateWord(ctx, zamek, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateWord nie powiodło się: %v", err)
+	}
+	_, err = mutationResolver.RestoreWord(ctx, word.ID, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.AlreadyExists, appErr.Code)
+	}
+	_, err = mutationResolver.DeleteWord(ctx, nil, &again.ID, nil)
+	assert.NoError(t, err)
+
+	// Przywrócenie słowa przywraca wszystko, co usunięto razem z nim
+	restoredWord, err := mutationResolver.RestoreWord(ctx, word.ID, nil)
+	if err != nil {
+		t.Fatalf("RestoreWord nie powiodło się: %v", err)
+	}
+	assert.Equal(t, zamek, restoredWord.Term)
+	translations, err = queryResolver.Translations(ctx, zamek, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(translations))
+	examples, err = queryResolver.Examples(ctx, zamek, lock)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(examples))
+	_, err = queryResolver.Node(ctx, inflection.ID)
+	assert.NoError(t, err)
+
+	// W koszu zostało tylko drugie słowo, a since pomija wcześniej usunięte
+	trash, err = queryResolver.Trash(ctx, nil, nil)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(trash)) {
+		assert.Equal(t, again.ID, trash[0].Node.(*model.Word).ID)
+	}
+	later := time.Now().Add(time.Hour)
+	trash, err = queryResolver.Trash(ctx, &later, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(trash))
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+
+}
+
+func TestRevisions(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+	var appErr *apperrors.Error
+
+	anna := models.WithAuthor(context.TODO(), "anna")
+	piotr := models.WithAuthor(context.TODO(), "piotr")
+	zamek := "zamek"
+	castle := "castle"
+	lock := "lock"
+	sentence := "Zamek stoi na wzgórzu."
+
+	// Słowo, tłumaczenie i przykład są zapisane jako trzy zmiany jednej mutacji
+	word, err := mutationResolver.CreateWord(anna, zamek, &castle, &sentence, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateWord nie powiodło się: %v", err)
+	}
+	translations, err := queryResolver.Translations(anna, zamek, nil, nil, nil)
+	if err != nil || len(translations) != 1 {
+		t.Fatalf("Translations nie powiodło się: %v", err)
+	}
+
+	// Kto i kiedy zmienił "castle" na "lock"
+	changed, err := mutationResolver.UpdateTranslation(piotr, translations[0].ID, &lock, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("UpdateTranslation nie powiodło się: %v", err)
+	}
+
+	// Słowa docelowe dodane przy okazji tłumaczenia mają własną historię
+	for _, targetWordID := range []string{translations[0].TargetWordID, changed.TargetWordID} {
+		targetHistory, err := queryResolver.History(anna, targetWordID)
+		if assert.NoError(t, err) && assert.Equal(t, 1, len(targetHistory)) {
+			assert.Equal(t, model.RevisionEntityWord, targetHistory[0].EntityType)
+			assert.Equal(t, model.RevisionActionCreate, targetHistory[0].Action)
+		}
+	}
+	// Zmiana, która niczego nie zmienia, nie jest zapisywana
+	_, err = mutationResolver.ReorderTranslations(piotr, zamek, []string{lock}, nil)
+	assert.NoError(t, err)
+
+	history, err := queryResolver.History(anna, word.ID)
+	if err != nil {
+		t.Fatalf("History nie powiodło się: %v", err)
+	}
+	if !assert.Equal(t, 4, len(history)) {
+		t.FailNow()
+	}
+	// Najnowsze zmiany są pierwsze
+	update := history[0]
+	assert.Equal(t, model.RevisionEntityTranslation, update.EntityType)
+	assert.Equal(t, translations[0].ID, update.EntityID)
+	assert.Equal(t, model.RevisionActionUpdate, update.Action)
+	assert.Equal(t, "updateTranslation", update.Mutation)
+	assert.Equal(t, word.ID, update.WordID)
+	if assert.NotNil(t, update.CreatedBy) {
+		assert.Equal(t, "piotr", *update.CreatedBy)
+	}
+	var before, after map[string]interface{}
+	if assert.NotNil(t, update.Before) && assert.NotNil(t, update.After) {
+		assert.NoError(t, json.Unmarshal([]byte(*update.Before), &before))
+		assert.NoError(t, json.Unmarshal([]byte(*update.After), &after))
+		assert.Equal(t, castle, before["targetTerm"])
+		assert.Equal(t, lock, after["targetTerm"])
+	}
+	assert.Equal(t, model.RevisionEntityExample, history[1].EntityType)
+	created := history[2]
+	assert.Equal(t, model.RevisionEntityTranslation, created.EntityType)
+	assert.Equal(t, model.RevisionActionCreate, created.Action)
+	assert.Nil(t, created.Before)
+	if assert.NotNil(t, created.CreatedBy) {
+		assert.Equal(t, "anna", *created.CreatedBy)
+	}
+	assert.Equal(t, model.RevisionEntityWord, history[3].EntityType)
+	assert.Equal(t, "createWord", history[3].Mutation)
+
+	// Zmiany są węzłami
+	node, err := queryResolver.Node(anna, update.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, update.ID, node.GetID())
+	}
+
+	// Powrót do stanu po utworzeniu tłumaczenia przywraca "castle" i sam jest zapisaną zmianą
+	reverted, err := mutationResolver.RevertToRevision(anna, created.ID, nil)
+	if err != nil {
+		t.Fatalf("RevertToRevision nie powiodło się: %v", err)
+	}
+	if assert.Equal(t, 1, len(reverted)) {
+		assert.Equal(t, model.RevisionActionUpdate, reverted[0].Action)
+		assert.Equal(t, "revertToRevision", reverted[0].Mutation)
+		// Migawki są porównywane jako JSON, bo baza zapisuje je w postaci jsonb
+		assert.JSONEq(t, *update.After, *reverted[0].Before)
+		assert.JSONEq(t, *created.After, *reverted[0].After)
+	}
+	translations, err = queryResolver.Translations(anna, zamek, nil, nil, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(translations)) {
+		assert.Equal(t, castle, translations[0].TargetTerm)
+	}
+
+	// Ponowny powrót niczego nie zmienia
+	reverted, err = mutationResolver.RevertToRevision(anna, created.ID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(reverted))
+
+	// Historia usuniętego słowa nadal jest dostępna, a powrót do jego utworzenia przywraca je z tłumaczeniem i przykładem
+	_, err = mutationResolver.DeleteWord(piotr, nil, &word.ID, nil)
+	assert.NoError(t, err)
+	history, err = queryResolver.History(anna, word.ID)
+	if assert.NoError(t, err) && assert.Equal(t, 8, len(history)) {
+		assert.Equal(t, model.RevisionActionDelete, history[2].Action)
+		assert.Equal(t, model.RevisionEntityWord, history[2].EntityType)
+	}
+	reverted, err = mutationResolver.RevertToRevision(anna, history[7].ID, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 3, len(reverted)) {
+		assert.Equal(t, model.RevisionEntityWord, reverted[0].EntityType)
+		for _, revision := range reverted {
+			assert.Equal(t, model.RevisionActionRestore, revision.Action)
+		}
+	}
+	translations, err = queryResolver.Translations(anna, zamek, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(translations))
+
+	// Nieznane słowo i nieznana zmiana
+	_, err = queryResolver.History(anna, "V29yZDo5OTk=") // Word:999
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.NotFound, appErr.Code)
+	}
+	_, err = mutationResolver.RevertToRevision(anna, word.ID, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+
+	// Formy usuniętego słowa nie można przywrócić, dopóki słowo jest w koszu
+	pies := "pies"
+	dog := "dog"
+	psu := "psu"
+	genitive := model.GrammaticalCaseGenitive
+	singular := model.GrammaticalNumberSingular
+	piesWord, err := mutationResolver.CreateWord(anna, pies, &dog, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateWord nie powiodło się: %v", err)
+	}
+	inflection, err := mutationResolver.CreateInflection(anna, &pies, nil, "psa", &genitive, &singular, nil)
+	if err != nil {
+		t.Fatalf("CreateInflection nie powiodło się: %v", err)
+	}
+	_, err = mutationResolver.UpdateInflection(anna, inflection.ID, &psu, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	history, err = queryResolver.History(anna, piesWord.ID)
+	if err != nil {
+		t.Fatalf("History nie powiodło się: %v", err)
+	}
+	var inflectionCreated *model.Revision
+	for _, revision := range history {
+		if revision.EntityType == model.RevisionEntityInflection && revision.Action == model.RevisionActionCreate {
+			inflectionCreated = revision
+		}
+	}
+	if !assert.NotNil(t, inflectionCreated) {
+		t.FailNow()
+	}
+	_, err = mutationResolver.DeleteWord(anna, nil, &piesWord.ID, nil)
+	assert.NoError(t, err)
+	_, err = mutationResolver.RevertToRevision(anna, inflectionCreated.ID, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+		assert.Equal(t, "revisionId", appErr.Field)
+	}
+	var stored models.Inflection
+	if assert.NoError(t, gormDB.First(&stored).Error) {
+		assert.Equal(t, psu, stored.Form)
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples, revisions RESTART IDENTITY CASCADE;")
+
+}
+
+func TestOptimisticLocking(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+	var appErr *apperrors.Error
+
+	ctx := context.TODO()
+	version := func(v int32) *int32 { return &v }
+	zamek := "zamek"
+	castle := "castle"
+	lock := "lock"
+	bolt := "bolt"
+
+	// Nowe encje mają wersję 1
+	word, err := mutationResolver.CreateWord(ctx, zamek, &castle, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateWord nie powiodło się: %v", err)
+	}
+	assert.Equal(t, int32(1), word.Version)
+	translations, err := queryResolver.Translations(ctx, zamek, nil, nil, nil)
+	if err != nil || len(translations) != 1 {
+		t.Fatalf("Translations nie powiodło się: %v", err)
+	}
+	assert.Equal(t, int32(1), translations[0].Version)
+
+	// Dwóch redaktorów zmienia to samo tłumaczenie od wersji 1: wygrywa pierwszy, drugi dostaje CONFLICT
+	updated, err := mutationResolver.UpdateTranslation(ctx, translations[0].ID, &lock, nil, nil, nil, nil, nil, version(1))
+	if err != nil {
+		t.Fatalf("UpdateTranslation nie powiodło się: %v", err)
+	}
+	assert.Equal(t, int32(2), updated.Version)
+
+	_, err = mutationResolver.UpdateTranslation(ctx, translations[0].ID, &bolt, nil, nil, nil, nil, nil, version(1))
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Conflict, appErr.Code)
+		assert.Equal(t, "expectedVersion", appErr.Field)
+		assert.Equal(t, 2, appErr.Extensions["currentVersion"])
+	}
+	translations, err = queryResolver.Translations(ctx, zamek, nil, nil, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(translations)) {
+		assert.Equal(t, lock, translations[0].TargetTerm)
+		assert.Equal(t, int32(2), translations[0].Version)
+	}
+
+	// Etykiety i nowy termin to jedna zmiana, więc wersja rośnie o 1; bez expectedVersion wygrywa ostatni zapis
+	domain := "hardware"
+	updated, err = mutationResolver.UpdateTranslation(ctx, translations[0].ID, &bolt, nil, nil, &domain, nil, nil, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int32(3), updated.Version)
+	}
+
+	// Zmiana, która niczego nie zmienia, nie zmienia też wersji
+	unchanged, err := mutationResolver.UpdateWord(ctx, word.ID, &zamek, nil, nil, nil, nil, nil, nil, version(1))
+	if assert.NoError(t, err) {
+		assert.Equal(t, int32(1), unchanged.Version)
+	}
+
+	// Usunięcie i przywrócenie to również zmiany
+	_, err = mutationResolver.DeleteWord(ctx, nil, &word.ID, version(0))
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+	_, err = mutationResolver.DeleteWord(ctx, nil, &word.ID, version(5))
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Conflict, appErr.Code)
+	}
+	_, err = mutationResolver.DeleteWord(ctx, nil, &word.ID, version(1))
+	assert.NoError(t, err)
+
+	_, err = mutationResolver.RestoreWord(ctx, word.ID, version(1))
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Conflict, appErr.Code)
+	}
+	restored, err := mutationResolver.RestoreWord(ctx, word.ID, version(2))
+	if assert.NoError(t, err) {
+		assert.Equal(t, int32(3), restored.Version)
+	}
+
+	// Przy powrocie do zmiany sprawdzana jest wersja zmienianej encji
+	history, err := queryResolver.History(ctx, word.ID)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	var created *model.Revision
+	for _, revision := range history {
+		if revision.EntityType == model.RevisionEntityTranslation && revision.Action == model.RevisionActionCreate {
+			created = revision
+		}
+	}
+	if created == nil {
+		t.Fatalf("Brak zmiany tworzącej tłumaczenie w historii")
+	}
+	_, err = mutationResolver.RevertToRevision(ctx, created.ID, version(3))
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Conflict, appErr.Code)
+	}
+	_, err = mutationResolver.RevertToRevision(ctx, created.ID, version(5))
+	assert.NoError(t, err)
+	translations, err = queryResolver.Translations(ctx, zamek, nil, nil, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(translations)) {
+		assert.Equal(t, castle, translations[0].TargetTerm)
+		assert.Equal(t, int32(6), translations[0].Version)
+	}
+
+	// Kolejność znaczeń należy do słowa: zmiana rang podnosi jego wersję, więc drugi redaktor dostaje CONFLICT
+	_, err = mutationResolver.CreateTranslation(ctx, &zamek, "fortress", nil, nil)
+	if err != nil {
+		t.Fatalf("CreateTranslation nie powiodło się: %v", err)
+	}
+	reordered, err := mutationResolver.ReorderTranslations(ctx, zamek, []string{"fortress", castle}, version(3))
+	if assert.NoError(t, err) && assert.Equal(t, 2, len(reordered)) {
+		assert.Equal(t, "fortress", reordered[0].TargetTerm)
+		assert.Equal(t, int32(2), reordered[0].Version)
+		assert.Equal(t, int32(7), reordered[1].Version)
+	}
+	_, err = mutationResolver.ReorderTranslations(ctx, zamek, []string{castle, "fortress"}, version(3))
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Conflict, appErr.Code)
+		assert.Equal(t, 4, appErr.Extensions["currentVersion"])
+	}
+	translations, err = queryResolver.Translations(ctx, zamek, nil, nil, nil)
+	if assert.NoError(t, err) && assert.Equal(t, 2, len(translations)) {
+		assert.Equal(t, "fortress", translations[0].TargetTerm)
+	}
+
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples, revisions RESTART IDENTITY CASCADE;")
+
+}
+
+func TestReplaceTranslationKeepsExamples(t *testing.T) {
+
+	gormDB, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: gormDB}
+	mutationResolver := resolver.Mutation()
+
+	zamek := "zamek"
+	castle := "castle"
+	sentence := "Zamknij drzwi na zamek."
+
+	mutationResolver.CreateWord(context.TODO(), "zamek", &castle, &sentence, nil, nil, nil, nil, nil)
+
+	// Przykłady przechodzą na nowe tłumaczenie
+	translation, err := mutationResolver.ReplaceTranslation(context.TODO(), &zamek, &castle, "lock", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("ReplaceTranslation nie powiodło się: %v", err)
+	}
+	assert.Equal(t, "lock", translation.EnglishWord)
+	examples, err := resolver.Translation().Examples(context.TODO(), translation)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(examples)) {
+		assert.Equal(t, sentence, examples[0].Sentence)
+	}
+
+	// Bez zachowania przykładów nowe tłumaczenie jest puste
+	preserve := false
+	// Tłumaczenie można też wskazać jego ID
+	translation, err = mutationResolver.ReplaceTranslation(context.TODO(), nil, nil, "zipper", &preserve, &translation.ID, nil)
+	if err != nil {
+		t.Fatalf("ReplaceTranslation nie powiodło się: %v", err)
+	}
+	examples, err = resolver.Translation().Examples(context.TODO(), translation)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(examples))
+
+	// Zachowane przykłady są sprawdzane z nowym tłumaczeniem według trybu walidacji
+	drzwi := "drzwi"
+	door := "door"
+	english := "en"
+	mutationResolver.CreateWord(context.TODO(), drzwi, &door, nil, nil, nil, nil, nil, nil)
+	_, err = mutationResolver.CreateExample(context.TODO(), &drzwi, &door, "Close the door.", nil, &english, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateExample nie powiodło się: %v", err)
+	}
+
+	strict := (&graph.Resolver{DB: gormDB, ExampleValidation: model.ExampleValidationReject}).Mutation()
+	_, err = strict.ReplaceTranslation(context.TODO(), &drzwi, &door, "gate", nil, nil, nil)
+	var appErr *apperrors.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+		assert.Equal(t, "newTranslation", appErr.Field)
+	}
+
+	flagging := (&graph.Resolver{DB: gormDB, ExampleValidation: model.ExampleValidationFlag}).Mutation()
+	translation, err = flagging.ReplaceTranslation(context.TODO(), &drzwi, &door, "gate", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("ReplaceTranslation nie powiodło się: %v", err)
+	}
+	examples, err = resolver.Translation().Examples(context.TODO(), translation)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(examples)) {
+		assert.True(t, examples[0].Flagged)
+	}
+	translation, err = flagging.ReplaceTranslation(context.TODO(), nil, nil, door, nil, &translation.ID, nil)
+	if err != nil {
+		t.Fatalf("ReplaceTranslation nie powiodło się: %v", err)
+	}
+	examples, err = resolver.Translation().Examples(context.TODO(), translation)
+	if assert.NoError(t, err) && assert.Equal(t, 1, len(examples)) {
+		assert.False(t, examples[0].Flagged)
+	}
 
-	assert.Error(t, err)
+	// Brakujące słowo jest zgłaszane na polu polishWord
+	missing := "brak"
+	_, err = mutationResolver.ReplaceTranslation(context.TODO(), &missing, &door, "gate", nil, nil, nil)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.NotFound, appErr.Code)
+		assert.Equal(t, "polishWord", appErr.Field)
+	}
 
-	gormDB.Exec("TRUNCATE words, translations, examples RESTART IDENTITY CASCADE;")
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples, revisions RESTART IDENTITY CASCADE;")
 
 }
 
-func TestDelete(t *testing.T) {
+func TestNode(t *testing.T) {
 
-	// Inicjalizujemy bazę danych w pamięci
 	gormDB, err := mockdatabase.MockDB(t)
 	if err != nil {
 		t.Fatalf("Nie udało się zainicjować mockowej bazy danych: %v", err)
 	}
 
-	// Tworzymy resolver z wykorzystaniem mockowanej bazy
 	resolver := &graph.Resolver{DB: gormDB}
 	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
 
 	b := "b"
 	c := "c"
 
-	mutationResolver.CreateWord(context.TODO(), "a", &b, &c)
-	mutationResolver.DeleteWord(context.TODO(), "a")
+	word, err := mutationResolver.CreateWord(context.TODO(), "a", &b, &c, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateWord nie powiodło się: %v", err)
+	}
 
-	// Sprawdzamy zawartość tabeli "words"
-	var words []model.Word
-	if err := gormDB.Find(&words).Error; err != nil {
-		t.Fatalf("Nie udało się pobrać danych z tabeli 'words': %v", err)
+	// Słowo pobrane po globalnym ID
+	node, err := queryResolver.Node(context.TODO(), word.ID)
+	if err != nil {
+		t.Fatalf("Node nie powiodło się: %v", err)
+	}
+	if assert.IsType(t, &model.Word{}, node) {
+		assert.Equal(t, "a", node.(*model.Word).PolishWord)
 	}
-	assert.Equal(t, 0, len(words))
 
-	var translations []model.Translation
-	if err := gormDB.Find(&translations).Error; err != nil {
-		t.Fatalf("Nie udało się pobrać danych z tabeli 'translations': %v", err)
+	node, err = queryResolver.Node(context.TODO(), "RXhhbXBsZTox") // Example:1
+	if assert.NoError(t, err) && assert.IsType(t, &model.Example{}, node) {
+		assert.Equal(t, "c", node.(*model.Example).Sentence)
 	}
-	assert.Equal(t, 0, len(translations))
 
-	var examples []model.Example
-	if err := gormDB.Find(&examples).Error; err != nil {
-		t.Fatalf("Nie udało się pobrać danych z tabeli 'examples': %v", err)
+	// Nieistniejący węzeł i niepoprawne ID
+	_, err = queryResolver.Node(context.TODO(), "V29yZDo5OQ==") // Word:99
+	var appErr *apperrors.Error
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.NotFound, appErr.Code)
 	}
-	assert.Equal(t, 0, len(examples))
+	_, err = queryResolver.Node(context.TODO(), "1")
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+
+	// Lista zachowuje kolejność, brakujące węzły są puste
+	nodes, err := queryResolver.Nodes(context.TODO(), []string{"VHJhbnNsYXRpb246MQ==", "V29yZDo5OQ==", word.ID})
+	if err != nil {
+		t.Fatalf("Nodes nie powiodło się: %v", err)
+	}
+	if assert.Equal(t, 3, len(nodes)) {
+		assert.IsType(t, &model.Translation{}, nodes[0])
+		assert.Nil(t, nodes[1])
+		assert.IsType(t, &model.Word{}, nodes[2])
+	}
+
+	// Mutacje przyjmują ID zamiast kluczy tekstowych
+	_, err = mutationResolver.CreateTranslation(context.TODO(), nil, "d", nil, &word.ID)
+	assert.NoError(t, err)
+	_, err = mutationResolver.CreateTranslation(context.TODO(), &b, "e", nil, &word.ID)
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, apperrors.Validation, appErr.Code)
+	}
+
+	deleted, err := mutationResolver.DeleteWord(context.TODO(), nil, &word.ID, nil)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
 
-	gormDB.Exec("TRUNCATE words, translations, examples RESTART IDENTITY CASCADE;")
+	gormDB.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
 
 }
 
@@ -249,9 +2121,327 @@ func TestCreateWordMutation(t *testing.T) {
 	if err := db.First(&word).Error; err != nil {
 		t.Fatalf("Word not found in database: %v", err)
 	}
-	assert.Equal(t, "a", word.PolishWord, "Unexpected value in database")
+	assert.Equal(t, "a", word.Term, "Unexpected value in database")
+
+	db.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+}
+
+func TestAuthorHeader(t *testing.T) {
+	db, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Failed to initialize mock database: %v", err)
+	}
+
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{DB: db}}))
+	srv.AddTransport(transport.POST{})
+	ts := httptest.NewServer(graph.AuthorMiddleware(srv))
+	defer ts.Close()
+
+	// Rows created by a request are attributed to the user of its X-User header
+	query := `{ "query": "mutation { createWord(polishWord: \"a\", englishWord: \"b\") { createdBy translations { createdBy } } }" }`
+	req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewBufferString(query))
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User", "anna")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			CreateWord struct {
+				CreatedBy    *string
+				Translations []struct {
+					CreatedBy *string
+				}
+			}
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if assert.NotNil(t, result.Data.CreateWord.CreatedBy) {
+		assert.Equal(t, "anna", *result.Data.CreateWord.CreatedBy)
+	}
+	if assert.Equal(t, 1, len(result.Data.CreateWord.Translations)) && assert.NotNil(t, result.Data.CreateWord.Translations[0].CreatedBy) {
+		assert.Equal(t, "anna", *result.Data.CreateWord.Translations[0].CreatedBy)
+	}
+
+	// Without the header rows have no author
+	resp, err = http.Post(ts.URL, "application/json", bytes.NewBufferString(`{ "query": "mutation { createWord(polishWord: \"c\") { createdBy } }" }`))
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	assert.Nil(t, result.Data.CreateWord.CreatedBy)
+
+	// An author set by an authentication middleware in front wins over the header
+	authenticated := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		graph.AuthorMiddleware(srv).ServeHTTP(w, r.WithContext(models.WithAuthor(r.Context(), "piotr")))
+	}))
+	defer authenticated.Close()
+
+	req, err = http.NewRequest(http.MethodPost, authenticated.URL, bytes.NewBufferString(`{ "query": "mutation { createWord(polishWord: \"d\") { createdBy } }" }`))
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User", "anna")
+
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if assert.NotNil(t, result.Data.CreateWord.CreatedBy) {
+		assert.Equal(t, "piotr", *result.Data.CreateWord.CreatedBy)
+	}
+
+	db.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+}
+
+func TestErrorCodes(t *testing.T) {
+	// Initialize mock database
+	db, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Failed to initialize mock database: %v", err)
+	}
+
+	// Create GraphQL server with test database
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{DB: db}}))
+
+	srv.AddTransport(transport.POST{})
+	srv.SetErrorPresenter(apperrors.Presenter)
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	// Create the word twice, the second request must fail with ALREADY_EXISTS
+	var result map[string]interface{}
+	for i := 0; i < 2; i++ {
+		query := `{ "query": "mutation { createWord(polishWord: \"a\") { id } }" }`
+		resp, err := http.Post(ts.URL, "application/json", bytes.NewBuffer([]byte(query)))
+		if err != nil {
+			t.Fatalf("Failed to execute request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		result = map[string]interface{}{}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+	}
+
+	errs, ok := result["errors"].([]interface{})
+	assert.True(t, ok, "Response errors missing")
+	extensions := errs[0].(map[string]interface{})["extensions"].(map[string]interface{})
+	assert.Equal(t, "ALREADY_EXISTS", extensions["code"])
+	assert.Equal(t, "polishWord", extensions["field"])
+
+	// Missing word is reported as NOT_FOUND
+	query := `{ "query": "{ translations(polishWord: \"b\") { id } }" }`
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewBuffer([]byte(query)))
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	result = map[string]interface{}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	errs, ok = result["errors"].([]interface{})
+	assert.True(t, ok, "Response errors missing")
+	extensions = errs[0].(map[string]interface{})["extensions"].(map[string]interface{})
+	assert.Equal(t, "NOT_FOUND", extensions["code"])
+
+	// An argument gqlgen cannot unmarshal is reported as VALIDATION
+	query = `{ "query": "{ trash(since: \"yesterday\") { deletedAt } }" }`
+	resp, err = http.Post(ts.URL, "application/json", bytes.NewBuffer([]byte(query)))
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	result = map[string]interface{}{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	errs, ok = result["errors"].([]interface{})
+	assert.True(t, ok, "Response errors missing")
+	extensions = errs[0].(map[string]interface{})["extensions"].(map[string]interface{})
+	assert.Equal(t, "VALIDATION", extensions["code"])
+
+	db.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+}
+
+func TestPanicIsInternal(t *testing.T) {
+	// A resolver without a database panics on its first query
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{}}))
+
+	srv.AddTransport(transport.POST{})
+	srv.SetErrorPresenter(apperrors.Presenter)
+	srv.SetRecoverFunc(apperrors.Recover)
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	query := `{ "query": "{ words { id } }" }`
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewBuffer([]byte(query)))
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	// The panic is reported as INTERNAL with the generic message only
+	errs, ok := result["errors"].([]interface{})
+	if assert.True(t, ok, "Response errors missing") {
+		presented := errs[0].(map[string]interface{})
+		assert.Equal(t, "internal server error", presented["message"])
+		assert.Equal(t, "INTERNAL", presented["extensions"].(map[string]interface{})["code"])
+	}
+}
+
+func TestDataLoader(t *testing.T) {
+	// Initialize mock database
+	db, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Failed to initialize mock database: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: db}
+	mutationResolver := resolver.Mutation()
+
+	// Three words, each with a translation and an example
+	for _, w := range []string{"a", "b", "c"} {
+		englishWord := w + "-en"
+		sentence := w + " sentence"
+		if _, err := mutationResolver.CreateWord(context.TODO(), w, &englishWord, &sentence, nil, nil, nil, nil, nil); err != nil {
+			t.Fatalf("CreateWord failed: %v", err)
+		}
+	}
+
+	// Count the SELECT queries sent while resolving the request
+	var queries int32
+	db.Callback().Query().After("gorm:query").Register("test:count_queries", func(*gorm.DB) {
+		atomic.AddInt32(&queries, 1)
+	})
+
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.POST{})
+
+	ts := httptest.NewServer(graph.LoaderMiddleware(db, srv))
+	defer ts.Close()
+
+	query := `{ "query": "{ words { polishWord translations { englishWord examples { sentence } } } }" }`
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewBuffer([]byte(query)))
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data struct {
+			Words []struct {
+				PolishWord   string
+				Translations []struct {
+					EnglishWord string
+					Examples    []struct{ Sentence string }
+				}
+			}
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+
+	if assert.Equal(t, 3, len(result.Data.Words)) {
+		for _, word := range result.Data.Words {
+			if assert.Equal(t, 1, len(word.Translations)) {
+				assert.Equal(t, word.PolishWord+"-en", word.Translations[0].EnglishWord)
+				assert.Equal(t, 1, len(word.Translations[0].Examples))
+			}
+		}
+	}
+	// One query for words, one for all translations and one for all examples
+	assert.Equal(t, int32(3), atomic.LoadInt32(&queries))
+
+	// Without nested fields in the selection, nothing else is loaded
+	atomic.StoreInt32(&queries, 0)
+	query = `{ "query": "{ words { polishWord } }" }`
+	resp, err = http.Post(ts.URL, "application/json", bytes.NewBuffer([]byte(query)))
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&queries))
+
+	db.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+}
+
+func TestLoadersClearedBetweenMutations(t *testing.T) {
+	// Initialize mock database
+	db, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Failed to initialize mock database: %v", err)
+	}
+
+	resolver := &graph.Resolver{DB: db}
+	englishWord := "castle"
+	word, err := resolver.Mutation().CreateWord(context.TODO(), "zamek", &englishWord, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateWord failed: %v", err)
+	}
+
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.POST{})
+	srv.AroundRootFields(graph.ClearLoadersOnMutation)
+
+	ts := httptest.NewServer(graph.LoaderMiddleware(db, srv))
+	defer ts.Close()
+
+	// The first field loads the translations, the second adds one and the third has to see it
+	query := fmt.Sprintf(`{ "query": "mutation { before: updateWord(id: \"%s\") { translations { englishWord } } added: createTranslation(polishWord: \"zamek\", englishWord: \"lock\") { id } after: updateWord(id: \"%s\") { translations { englishWord } } }" }`, word.ID, word.ID)
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewBuffer([]byte(query)))
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	type translations struct {
+		Translations []struct{ EnglishWord string }
+	}
+	var result struct {
+		Data struct {
+			Before translations
+			After  translations
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
 
-	db.Exec("TRUNCATE words, translations, examples RESTART IDENTITY CASCADE;")
+	assert.Equal(t, 1, len(result.Data.Before.Translations), "Unexpected translations before the change")
+	assert.Equal(t, 2, len(result.Data.After.Translations), "Translations cached before the change were returned")
+
+	db.Exec("TRUNCATE words, inflections, translations, sentences, examples, revisions RESTART IDENTITY CASCADE;")
 }
 
 func TestConcurrentCreateWordMutations(t *testing.T) {
@@ -306,7 +2496,7 @@ func TestConcurrentCreateWordMutations(t *testing.T) {
 	}
 	assert.Equal(t, int64(10), count, "Unexpected number of words in database")
 
-	db.Exec("TRUNCATE words, translations, examples RESTART IDENTITY CASCADE;")
+	db.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
 }
 
 func TestConcurrentLocking(t *testing.T) {
@@ -361,7 +2551,7 @@ func TestConcurrentLocking(t *testing.T) {
 	}
 	assert.Equal(t, int64(2), count, "Unexpected number of words in database")
 
-	db.Exec("TRUNCATE words, translations, examples RESTART IDENTITY CASCADE;")
+	db.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
 }
 
 func TestConcurrentTrnaslations(t *testing.T) {
@@ -417,7 +2607,7 @@ func TestConcurrentTrnaslations(t *testing.T) {
 	if err := db.First(&word).Error; err != nil {
 		t.Fatalf("Word not found in database: %v", err)
 	}
-	assert.Equal(t, "a", word.PolishWord, "Unexpected value in database")
+	assert.Equal(t, "a", word.Term, "Unexpected value in database")
 
 	// Prepare GraphQL Translations
 
@@ -453,5 +2643,88 @@ func TestConcurrentTrnaslations(t *testing.T) {
 	}
 	assert.Equal(t, int64(10), count, "Unexpected number of translations in database")
 
-	db.Exec("TRUNCATE words, translations, examples RESTART IDENTITY CASCADE;")
+	// Verify that concurrent translations were ranked one after another
+	var ranks []int
+	if err := db.Model(&models.Translation{}).Order("rank").Pluck("rank", &ranks).Error; err != nil {
+		t.Fatalf("Failed to read ranks: %v", err)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, ranks, "Unexpected translation ranks")
+
+	db.Exec("TRUNCATE words, inflections, translations, sentences, examples RESTART IDENTITY CASCADE;")
+}
+
+func TestConcurrentVersionConflicts(t *testing.T) {
+	// Initialize mock database
+	db, err := mockdatabase.MockDB(t)
+	if err != nil {
+		t.Fatalf("Failed to initialize mock database: %v", err)
+	}
+
+	// Create GraphQL server with test database
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{DB: db}}))
+
+	srv.AddTransport(transport.POST{})
+	srv.SetErrorPresenter(apperrors.Presenter)
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	// Create the word with one translation, both at version 1
+	query := `{ "query": "mutation { createWord(polishWord: \"zamek\", englishWord: \"castle\") { id version } }" }`
+	resp, err := http.Post(ts.URL, "application/json", bytes.NewBuffer([]byte(query)))
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var translation models.Translation
+	if err := db.First(&translation).Error; err != nil {
+		t.Fatalf("Translation not found in database: %v", err)
+	}
+	translationID := graph.ToGraphQLTranslation(&translation).ID
+
+	// Editors who all read version 1 update the translation at once
+	words := []string{"lock", "bolt", "latch", "padlock", "clasp", "catch", "hasp", "fastener", "closure", "zipper"}
+	var wg sync.WaitGroup
+	var succeeded, conflicted int32
+
+	for _, word := range words {
+		wg.Add(1)
+		go func(w string) {
+			defer wg.Done()
+			query := fmt.Sprintf(`{ "query": "mutation { updateTranslation(id: \"%s\", targetTerm: \"%s\", expectedVersion: 1) { version } }" }`, translationID, w)
+			resp, err := http.Post(ts.URL, "application/json", bytes.NewBuffer([]byte(query)))
+			if err != nil {
+				t.Errorf("Failed to execute request: %v", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Errorf("Failed to parse response: %v", err)
+				return
+			}
+			if errs, ok := result["errors"].([]interface{}); ok {
+				extensions := errs[0].(map[string]interface{})["extensions"].(map[string]interface{})
+				assert.Equal(t, "CONFLICT", extensions["code"])
+				atomic.AddInt32(&conflicted, 1)
+				return
+			}
+			atomic.AddInt32(&succeeded, 1)
+		}(word)
+	}
+
+	wg.Wait()
+
+	// Exactly one editor wins, the others are told the translation changed instead of overwriting it
+	assert.Equal(t, int32(1), succeeded, "Unexpected number of successful updates")
+	assert.Equal(t, int32(len(words)-1), conflicted, "Unexpected number of conflicts")
+	if err := db.First(&translation, translation.ID).Error; err != nil {
+		t.Fatalf("Translation not found in database: %v", err)
+	}
+	assert.Equal(t, 2, translation.Version, "Unexpected version in database")
+
+	db.Exec("TRUNCATE words, inflections, translations, sentences, examples, revisions RESTART IDENTITY CASCADE;")
 }
@@ -3,8 +3,12 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
+	"translatorapi/apperrors"
 	"translatorapi/database"
 	"translatorapi/graph"
+	"translatorapi/graph/model"
+	"translatorapi/lemmatizer"
 
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/lru"
@@ -30,13 +34,38 @@ func main() {
 	// 	Resolvers: &graph.Resolver{DB: db}, // Make sure Resolver is correctly implemented
 	// }))
 
-	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{DB: db}}))
+	resolver := &graph.Resolver{DB: db}
+
+	// A morphological dictionary, if configured, replaces the built-in rule-based lemmatizer
+	if path := os.Getenv("LEMMATIZER_DICTIONARY"); path != "" {
+		dictionary, err := lemmatizer.LoadDictionary(path)
+		if err != nil {
+			log.Fatalf("Nie udało się wczytać słownika lematyzatora: %v", err)
+		}
+		resolver.Lemmatizer = dictionary
+	}
+
+	// Server-wide check of new example sentences: OFF (default), FLAG or REJECT
+	if mode := os.Getenv("EXAMPLE_VALIDATION"); mode != "" {
+		validation := model.ExampleValidation(mode)
+		if !validation.IsValid() {
+			log.Fatalf("Nieznany tryb EXAMPLE_VALIDATION: %s", mode)
+		}
+		resolver.ExampleValidation = validation
+	}
+
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
 
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
 
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	// Expose typed error codes and hide database errors and panics from clients
+	srv.SetErrorPresenter(apperrors.Presenter)
+	srv.SetRecoverFunc(apperrors.Recover)
+	// Mutation fields change what earlier fields of the request loaded
+	srv.AroundRootFields(graph.ClearLoadersOnMutation)
 
 	srv.Use(extension.Introspection{})
 	srv.Use(extension.AutomaticPersistedQuery{
@@ -45,8 +74,10 @@ func main() {
 
 	// Serve the GraphQL playground at root
 	http.Handle("/", playground.Handler("GraphQL Playground", "/query"))
-	// Handle queries at /query
-	http.Handle("/query", srv)
+	// Handle queries at /query, each request gets its own DataLoaders
+	// and creates rows on behalf of the user named in its X-User header.
+	// The header is trusted as is, so the server must run behind a proxy which authenticates users and sets it
+	http.Handle("/query", graph.AuthorMiddleware(graph.LoaderMiddleware(db, srv)))
 
 	// Start the server
 	log.Println("Server running on http://localhost:8080/")